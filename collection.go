@@ -1,6 +1,9 @@
 package temple
 
-import "errors"
+import (
+	"errors"
+	"reflect"
+)
 
 type List []interface{}
 
@@ -23,7 +26,7 @@ func Contains(v interface{}, c interface{}) (bool, error) {
 	switch a := c.(type) {
 	case List:
 		for _, e := range a {
-			if e == v {
+			if reflect.DeepEqual(e, v) {
 				in = true
 				break
 			}
@@ -32,7 +35,7 @@ func Contains(v interface{}, c interface{}) (bool, error) {
 		_, ok := a[v]
 		in = ok
 	default:
-		return false, errors.New("invalid collection")
+		return false, funcError("Contains", errors.New("invalid collection"), v, c)
 	}
 	return in, nil
 }
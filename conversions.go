@@ -1,17 +1,38 @@
 package temple
 
-import "github.com/spf13/cast"
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
 
+	"github.com/spf13/cast"
+)
+
+// ToInt converts a value to an int. Numbers outside of the range of an int
+// result in ErrOverflow instead of wrapping around.
 func ToInt(v interface{}) (int, error) {
-	return cast.ToIntE(v)
+	c := castable(v)
+	if f, ok := c.(float64); ok && !(f >= -maxIntFloat && f < maxIntFloat) {
+		return 0, funcError("ToInt", ErrOverflow, v)
+	}
+	i, err := cast.ToIntE(c)
+	return i, funcError("ToInt", err, v)
 }
 
+// maxIntFloat is 2^63 on 64-bit platforms, the smallest float64 above the
+// range of an int.
+var maxIntFloat = math.Ldexp(1, strconv.IntSize-1)
+
 func ToFloat64(v interface{}) (float64, error) {
-	return cast.ToFloat64E(v)
+	f, err := cast.ToFloat64E(castable(v))
+	return f, funcError("ToFloat64", err, v)
 }
 
 func ToString(v interface{}) (string, error) {
-	return cast.ToStringE(v)
+	s, err := cast.ToStringE(v)
+	return s, funcError("ToString", err, v)
 }
 
 func ToIntSlice(vals []interface{}) ([]int, error) {
@@ -20,7 +41,7 @@ func ToIntSlice(vals []interface{}) ([]int, error) {
 	for i, v := range vals {
 		out[i], err = ToInt(v)
 		if err != nil {
-			return nil, err
+			return nil, funcError("ToIntSlice", err, vals)
 		}
 	}
 	return out, nil
@@ -32,7 +53,7 @@ func ToFloat64Slice(vals []interface{}) ([]float64, error) {
 	for i, v := range vals {
 		out[i], err = ToFloat64(v)
 		if err != nil {
-			return nil, err
+			return nil, funcError("ToFloat64Slice", err, vals)
 		}
 	}
 	return out, nil
@@ -44,8 +65,30 @@ func ToStringSlice(vals []interface{}) ([]string, error) {
 	for i, v := range vals {
 		out[i], err = ToString(v)
 		if err != nil {
-			return nil, err
+			return nil, funcError("ToStringSlice", err, vals)
 		}
 	}
 	return out, nil
 }
+
+// castable converts a json.Number or a big number to an int64 or float64,
+// which the cast package can convert. Numbers outside of the range of an int64
+// are converted to the nearest float64.
+func castable(v interface{}) interface{} {
+	switch n := v.(type) {
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return i
+		} else if f, err := n.Float64(); err == nil {
+			return f
+		}
+	case *big.Int, *big.Float, *big.Rat:
+		if n, ok := bigNumber(reflect.ValueOf(n)); ok {
+			if n.float || n.r != nil {
+				return n.float64()
+			}
+			return n.i
+		}
+	}
+	return v
+}
@@ -0,0 +1,60 @@
+package temple
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestToInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		want    int
+		wantErr error
+	}{
+		{name: "int", v: 3, want: 3},
+		{name: "float", v: 2.9, want: 2},
+		{name: "json.Number", v: json.Number("9007199254740993"), want: 9007199254740993},
+		{name: "json.Number fraction", v: json.Number("2.5"), want: 2},
+		{name: "json.Number overflow", v: json.Number("1180591620717411303424"), wantErr: ErrOverflow},
+		{name: "json.Number negative overflow", v: json.Number("-1e19"), wantErr: ErrOverflow},
+		{name: "float overflow", v: 1e300, wantErr: ErrOverflow},
+		{name: "*big.Int", v: big.NewInt(-5), want: -5},
+		{name: "*big.Int overflow", v: new(big.Int).Lsh(big.NewInt(1), 70), wantErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToInt(tt.v)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ToInt() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToInt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want float64
+	}{
+		{name: "int", v: 3, want: 3},
+		{name: "json.Number", v: json.Number("1180591620717411303424"), want: 1 << 70},
+		{name: "*big.Int", v: new(big.Int).Lsh(big.NewInt(1), 70), want: 1 << 70},
+		{name: "*big.Int negative", v: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(3), 80)), want: -3 << 80},
+		{name: "*big.Rat", v: big.NewRat(1, 4), want: 0.25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToFloat64(tt.v)
+			if err != nil || got != tt.want {
+				t.Errorf("ToFloat64() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
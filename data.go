@@ -0,0 +1,125 @@
+package temple
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// NumberMode determines the Go type of the numbers decoded by DecodeJSON.
+type NumberMode int
+
+const (
+	// NumbersFloat64 decodes every number as a float64. This is the behavior
+	// of json.Unmarshal, and integers larger than 2^53 lose precision.
+	NumbersFloat64 NumberMode = iota
+	// NumbersJSON decodes every number as a json.Number, which keeps the
+	// number's text.
+	NumbersJSON
+	// NumbersInt64 decodes integers as int64 and other numbers as float64.
+	// Integers that do not fit in an int64 are decoded as json.Number.
+	NumbersInt64
+	// NumbersBig is like NumbersInt64, except that integers that do not fit
+	// in an int64 are decoded as *big.Int, and other numbers that do not fit
+	// in a float64 as *big.Float.
+	NumbersBig
+)
+
+var numberModeNames = map[NumberMode]string{
+	NumbersFloat64: "float64",
+	NumbersJSON:    "number",
+	NumbersInt64:   "int64",
+	NumbersBig:     "big",
+}
+
+// String returns the name of the mode as accepted by ParseNumberMode.
+func (m NumberMode) String() string {
+	if s, ok := numberModeNames[m]; ok {
+		return s
+	}
+	return fmt.Sprintf("NumberMode(%d)", int(m))
+}
+
+// ParseNumberMode parses the name of a number mode. The valid names are
+// float64, number, int64 and big.
+func ParseNumberMode(s string) (NumberMode, error) {
+	for m, name := range numberModeNames {
+		if strings.EqualFold(s, name) {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("temple: unknown number mode %q", s)
+}
+
+// DecodeJSON decodes a single JSON value into the types produced by
+// json.Unmarshal when decoding into an interface{}, except that numbers are
+// decoded according to mode. Every numeric function provided by temple
+// accepts the resulting numbers.
+func DecodeJSON(b []byte, mode NumberMode) (interface{}, error) {
+	if mode == NumbersFloat64 {
+		var data interface{}
+		err := json.Unmarshal(b, &data)
+		return data, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+	// Match json.Unmarshal, which rejects trailing data.
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid character after top-level value")
+	}
+
+	if mode == NumbersInt64 || mode == NumbersBig {
+		data = int64Numbers(data, mode == NumbersBig)
+	}
+	return data, nil
+}
+
+// int64Numbers replaces the json.Numbers in v with int64 and float64 values.
+// If useBig is true, the numbers that do not fit are replaced with *big.Int and
+// *big.Float values.
+func int64Numbers(v interface{}, useBig bool) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if strings.ContainsAny(string(v), ".eE") {
+			if f, err := v.Float64(); err == nil {
+				return f
+			}
+		}
+		if useBig {
+			return bigJSONNumber(v)
+		}
+		return v
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = int64Numbers(e, useBig)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = int64Numbers(e, useBig)
+		}
+	}
+	return v
+}
+
+// bigJSONNumber converts a number that does not fit in an int64 or float64.
+func bigJSONNumber(n json.Number) interface{} {
+	if i, ok := new(big.Int).SetString(string(n), 10); ok {
+		return i
+	}
+	if f, _, err := big.ParseFloat(string(n), 10, 256, big.ToNearestEven); err == nil {
+		return f
+	}
+	return n
+}
@@ -0,0 +1,152 @@
+package temple
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	texttmpl "text/template"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	const input = `{"id": 9007199254740993, "price": 1.5, "qty": 3, "huge": 12345678901234567890, "exp": 1e3, "items": [1, 2.5]}`
+
+	tests := []struct {
+		name    string
+		mode    NumberMode
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "float64",
+			mode: NumbersFloat64,
+			want: map[string]interface{}{
+				"id": float64(9007199254740992), "price": 1.5, "qty": float64(3), "huge": 12345678901234567890.0,
+				"exp": float64(1000), "items": []interface{}{float64(1), 2.5},
+			},
+		},
+		{
+			name: "number",
+			mode: NumbersJSON,
+			want: map[string]interface{}{
+				"id": json.Number("9007199254740993"), "price": json.Number("1.5"), "qty": json.Number("3"),
+				"huge": json.Number("12345678901234567890"), "exp": json.Number("1e3"),
+				"items": []interface{}{json.Number("1"), json.Number("2.5")},
+			},
+		},
+		{
+			name: "int64",
+			mode: NumbersInt64,
+			want: map[string]interface{}{
+				"id": int64(9007199254740993), "price": 1.5, "qty": int64(3), "huge": json.Number("12345678901234567890"),
+				"exp": float64(1000), "items": []interface{}{int64(1), 2.5},
+			},
+		},
+		{
+			name: "big",
+			mode: NumbersBig,
+			want: map[string]interface{}{
+				"id": int64(9007199254740993), "price": 1.5, "qty": int64(3), "huge": bigInt("12345678901234567890"),
+				"exp": float64(1000), "items": []interface{}{int64(1), 2.5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeJSON([]byte(input), tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeJSON() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	for _, mode := range []NumberMode{NumbersFloat64, NumbersJSON, NumbersInt64, NumbersBig} {
+		if _, err := DecodeJSON([]byte(`{} x`), mode); err == nil {
+			t.Errorf("DecodeJSON() with %v mode accepted trailing data", mode)
+		}
+	}
+
+	got, err := DecodeJSON([]byte(`[1.5e400]`), NumbersBig)
+	if err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+	if f, ok := got.([]interface{})[0].(*big.Float); !ok || f.Text('g', 3) != "1.5e+400" {
+		t.Errorf("DecodeJSON() = %#v, want a *big.Float of 1.5e400", got)
+	}
+}
+
+func TestParseNumberMode(t *testing.T) {
+	for _, mode := range []NumberMode{NumbersFloat64, NumbersJSON, NumbersInt64, NumbersBig} {
+		got, err := ParseNumberMode(mode.String())
+		if err != nil || got != mode {
+			t.Errorf("ParseNumberMode(%q) = %v, %v, want %v", mode.String(), got, err, mode)
+		}
+	}
+	if _, err := ParseNumberMode("decimal"); err == nil {
+		t.Error("ParseNumberMode() accepted an unknown mode")
+	}
+}
+
+func TestBigNumbers(t *testing.T) {
+	data, err := DecodeJSON([]byte(`{"balance": 123456789012345678901234, "fee": 21000}`), NumbersBig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := texttmpl.Must(texttmpl.New("").Funcs(FullFuncMap().Text()).Parse(
+		`{{ .balance }} {{ Commas .balance }} {{ .balance | ScaleDecimals 18 | DecFormat 2 | Commas }} ` +
+			`{{ DecSub .balance .fee }} {{ Max .fee 1 }} {{ Max .balance .fee }} {{ CompactNumber .balance }}`,
+	))
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := "123456789012345678901234 123,456,789,012,345,678,901,234 123,456.79 " +
+		"123456789012345678880234 21000 123456789012345678901234 123456789Q"
+	if got := b.String(); got != want {
+		t.Errorf("Execute() = %q, want %q", got, want)
+	}
+
+	if _, err := IntSum(data.(map[string]interface{})["balance"], 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("IntSum() error = %v, want ErrOverflow", err)
+	}
+}
+
+func TestDecodedNumbers(t *testing.T) {
+	const input = `{"id": 9007199254740993, "a": 6, "b": 4, "f": 2.5, "list": [3, 1, 2]}`
+
+	tmpl := `{{ .id }} {{ Max .a .b }} {{ IntMax .list }} {{ Sum .a .b .f }} {{ Diff .a .b }} {{ Mul .a .f }} ` +
+		`{{ Div .a .b }} {{ Mod .a .b }} {{ Ceil .f }} {{ Floor .f }} {{ ToInt .a }} {{ ToFloat64 .f }} ` +
+		`{{ ToString .id }} {{ DecAdd .f .a }} {{ IntSum .a .b }} {{ IntDiff .a .b }} {{ Mean .a .f }}`
+
+	tests := []struct {
+		mode NumberMode
+		want string
+	}{
+		{mode: NumbersJSON, want: "9007199254740993 6 3 12.5 2 15 1.5 2 3 2 6 2.5 9007199254740993 8.5 10 2 4.25"},
+		{mode: NumbersInt64, want: "9007199254740993 6 3 12.5 2 15 1.5 2 3 2 6 2.5 9007199254740993 8.5 10 2 4.25"},
+		{mode: NumbersBig, want: "9007199254740993 6 3 12.5 2 15 1.5 2 3 2 6 2.5 9007199254740993 8.5 10 2 4.25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			data, err := DecodeJSON([]byte(input), tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tmpl := texttmpl.Must(texttmpl.New("").Funcs(FullFuncMap().Text()).Parse(tmpl))
+			var b strings.Builder
+			if err := tmpl.Execute(&b, data); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
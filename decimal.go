@@ -0,0 +1,469 @@
+package temple
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Rounding modes accepted by DecRound.
+const (
+	// RoundHalfUp rounds halfway values away from zero, so 2.345 rounds to
+	// 2.35 and -2.345 rounds to -2.35. This is the default mode.
+	RoundHalfUp = "half-up"
+	// RoundHalfEven rounds halfway values to the nearest even digit, so 2.345
+	// rounds to 2.34 and 2.355 rounds to 2.36. This is also known as banker's
+	// rounding.
+	RoundHalfEven = "half-even"
+	// RoundTowardZero discards the digits after the last decimal place, so
+	// 2.349 rounds to 2.34 and -2.349 rounds to -2.34.
+	RoundTowardZero = "toward-zero"
+)
+
+// Decimal is an arbitrary precision decimal number. Unlike float64, decimal
+// fractions such as 0.1 are represented exactly, so sums of money do not
+// accumulate rounding errors. The zero value is 0.
+type Decimal struct {
+	// The value is unscaled * 10^-scale.
+	unscaled *big.Int
+	scale    int
+}
+
+// MaxDecimalPlaces bounds the exponents of parsed decimals and the number of
+// places that decimals are rounded or shifted to, in either direction. Larger
+// values would let a template spend unbounded time and memory computing powers
+// of ten, so they result in an error wrapping ErrOverflow.
+const MaxDecimalPlaces = 10000
+
+// ParseDecimal parses a decimal number such as "-1234.50" or "1.5e3". The
+// exponent must not exceed MaxDecimalPlaces.
+func ParseDecimal(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+
+	exp := 0
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.Atoi(str[i+1:])
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return Decimal{}, placesError("exponent", str[i+1:])
+			}
+			return Decimal{}, ErrInvalidDecimal
+		}
+		if err := checkPlaces("exponent", e); err != nil {
+			return Decimal{}, err
+		}
+		str, exp = str[:i], e
+	}
+
+	intPart, frac := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		intPart, frac = str[:i], str[i+1:]
+	}
+
+	digits := intPart + frac
+	sign := ""
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, ErrInvalidDecimal
+	}
+
+	u, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return Decimal{}, ErrInvalidDecimal
+	}
+	return Decimal{unscaled: u, scale: len(frac) - exp}.normalize(), nil
+}
+
+// Dec converts a value to a Decimal. Strings, json.Number values, and numbers
+// of any kind are accepted. Floats are converted using the shortest decimal
+// representation that round trips, so Dec 0.1 is exactly 0.1. A *big.Int or
+// *big.Rat is converted exactly, except that a fraction without a finite
+// decimal representation, such as 1/3, is rounded to 34 decimal places.
+func Dec(v interface{}) (Decimal, error) {
+	d, err := toDecimal(v)
+	return d, funcError("Dec", err, v)
+}
+
+func toDecimal(v interface{}) (Decimal, error) {
+	switch v := v.(type) {
+	case Decimal:
+		return v, nil
+	case *Decimal:
+		if v == nil {
+			return Decimal{}, ErrInvalidDecimal
+		}
+		return *v, nil
+	case json.Number:
+		return ParseDecimal(string(v))
+	case *big.Int:
+		if v == nil {
+			return Decimal{}, ErrInvalidDecimal
+		}
+		return Decimal{unscaled: new(big.Int).Set(v)}, nil
+	case *big.Float:
+		if v == nil || v.IsInf() {
+			return Decimal{}, ErrInvalidDecimal
+		}
+		// Each decimal place takes more than three bits of exponent.
+		if e := v.MantExp(nil); e > 4*MaxDecimalPlaces || e < -4*MaxDecimalPlaces {
+			return Decimal{}, fmt.Errorf("%w: number exceeds %d places", ErrOverflow, MaxDecimalPlaces)
+		}
+		return ParseDecimal(v.Text('f', -1))
+	case *big.Rat:
+		if v == nil {
+			return Decimal{}, ErrInvalidDecimal
+		}
+		return ratDecimal(v), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Decimal{unscaled: big.NewInt(rv.Int())}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return Decimal{unscaled: new(big.Int).SetUint64(rv.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return Decimal{}, ErrInvalidDecimal
+		}
+		bits := 64
+		if rv.Kind() == reflect.Float32 {
+			bits = 32
+		}
+		return ParseDecimal(strconv.FormatFloat(f, 'f', -1, bits))
+	case reflect.String:
+		return ParseDecimal(rv.String())
+	}
+	return Decimal{}, ErrInvalidType
+}
+
+// maxRatScale is the number of decimal places kept when converting a fraction
+// without a finite decimal representation.
+const maxRatScale = 34
+
+// ratDecimal converts a fraction. It is exact if the denominator has no prime
+// factors other than 2 and 5.
+func ratDecimal(r *big.Rat) Decimal {
+	// Find the smallest scale for which the denominator divides 10^scale.
+	denom := new(big.Int).Set(r.Denom())
+	twos, fives := 0, 0
+	for denom.Bit(0) == 0 {
+		denom.Rsh(denom, 1)
+		twos++
+	}
+	five, rem := big.NewInt(5), new(big.Int)
+	for {
+		q, _ := new(big.Int).QuoRem(denom, five, rem)
+		if rem.Sign() != 0 {
+			break
+		}
+		denom = q
+		fives++
+	}
+
+	scale := twos
+	if fives > scale {
+		scale = fives
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		scale = maxRatScale
+	}
+
+	// The numerator times 10^scale / denominator, rounded half-up.
+	num := new(big.Int).Mul(r.Num(), pow10(scale))
+	q, m := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if m.Abs(m).Lsh(m, 1).Cmp(r.Denom()) >= 0 {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Decimal{unscaled: q, scale: scale}
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// normalize ensures that the scale is not negative.
+func (d Decimal) normalize() Decimal {
+	if d.scale >= 0 {
+		return d
+	}
+	u := new(big.Int).Mul(d.int(), pow10(-d.scale))
+	return Decimal{unscaled: u, scale: 0}
+}
+
+// rescale returns d with the provided scale, which must not be smaller than
+// the scale of d.
+func (d Decimal) rescale(scale int) Decimal {
+	if scale == d.scale {
+		return d
+	}
+	u := new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+	return Decimal{unscaled: u, scale: scale}
+}
+
+// Add returns d + e.
+func (d Decimal) Add(e Decimal) Decimal {
+	scale := d.scale
+	if e.scale > scale {
+		scale = e.scale
+	}
+	a, b := d.rescale(scale), e.rescale(scale)
+	return Decimal{unscaled: new(big.Int).Add(a.int(), b.int()), scale: scale}
+}
+
+// Sub returns d - e.
+func (d Decimal) Sub(e Decimal) Decimal {
+	return d.Add(e.Neg())
+}
+
+// Mul returns d * e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Cmp compares d and e and returns -1, 0 or +1.
+func (d Decimal) Cmp(e Decimal) int {
+	return d.Sub(e).Sign()
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int { return d.int().Sign() }
+
+// Round rounds d to the provided number of decimal places using the provided
+// mode. If d has fewer decimal places, trailing zeros are added. A negative
+// number of places rounds to a power of ten, so rounding 1250 to -2 places
+// results in 1300. An empty mode is RoundHalfUp. The number of places must not
+// exceed MaxDecimalPlaces.
+func (d Decimal) Round(places int, mode string) (Decimal, error) {
+	switch mode {
+	case "", RoundHalfUp, RoundHalfEven, RoundTowardZero:
+	default:
+		return Decimal{}, fmt.Errorf("unknown rounding mode %q", mode)
+	}
+	if err := checkPlaces("places", places); err != nil {
+		return Decimal{}, err
+	}
+
+	if d.scale <= places {
+		return d.rescale(places), nil
+	}
+
+	div := pow10(d.scale - places)
+	q, r := new(big.Int).QuoRem(d.int(), div, new(big.Int))
+
+	// Compare twice the remainder to the divisor to find halfway values.
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	c := half.Cmp(div)
+
+	var away bool
+	switch mode {
+	case "", RoundHalfUp:
+		away = c >= 0
+	case RoundHalfEven:
+		away = c > 0 || (c == 0 && q.Bit(0) == 1)
+	}
+
+	if away {
+		if d.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Decimal{unscaled: q, scale: places}.normalize(), nil
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.int(), pow10(d.scale)).Float64()
+	return f
+}
+
+// String returns d in decimal notation without an exponent, such as "-12.50".
+func (d Decimal) String() string {
+	s := new(big.Int).Abs(d.int()).String()
+	if d.scale > 0 {
+		if len(s) <= d.scale {
+			s = strings.Repeat("0", d.scale-len(s)+1) + s
+		}
+		s = s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+	}
+	if d.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// MarshalJSON implements json.Marshaler. Decimals are encoded as JSON numbers.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// checkPlaces checks that a number of places or an exponent does not exceed
+// MaxDecimalPlaces.
+func checkPlaces(what string, n int) error {
+	if n > MaxDecimalPlaces || n < -MaxDecimalPlaces {
+		return placesError(what, n)
+	}
+	return nil
+}
+
+func placesError(what string, n interface{}) error {
+	return fmt.Errorf("%w: %s %v exceeds %d places", ErrOverflow, what, n, MaxDecimalPlaces)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// DecAdd adds decimals. The arguments are converted as by Dec.
+func DecAdd(x interface{}, vals ...interface{}) (Decimal, error) {
+	return decFold("DecAdd", Decimal.Add, x, vals)
+}
+
+// DecSub subtracts each of the values from x. The arguments are converted as
+// by Dec.
+func DecSub(x interface{}, vals ...interface{}) (Decimal, error) {
+	return decFold("DecSub", Decimal.Sub, x, vals)
+}
+
+// DecMul multiplies decimals. The arguments are converted as by Dec.
+func DecMul(x interface{}, vals ...interface{}) (Decimal, error) {
+	return decFold("DecMul", Decimal.Mul, x, vals)
+}
+
+func decFold(name string, op func(Decimal, Decimal) Decimal, x interface{}, vals []interface{}) (Decimal, error) {
+	d, err := toDecimal(x)
+	if err != nil {
+		return Decimal{}, funcError(name, err, append([]interface{}{x}, vals...)...)
+	}
+
+	for _, v := range vals {
+		e, err := toDecimal(v)
+		if err != nil {
+			return Decimal{}, funcError(name, err, append([]interface{}{x}, vals...)...)
+		}
+		d = op(d, e)
+	}
+	return d, nil
+}
+
+// DecRound rounds a decimal to the provided number of decimal places. The
+// decimal is the last argument so that it can be piped. It may be preceded by
+// a rounding mode, either "half-up" (the default), "half-even" or
+// "toward-zero":
+//		{{ DecMul .Price .Qty | DecRound 2 }}
+//		{{ DecMul .Price .Qty | DecRound 2 "half-even" }}
+func DecRound(places int, args ...interface{}) (Decimal, error) {
+	d, err := roundArgs(places, args)
+	if err != nil {
+		return Decimal{}, funcError("DecRound", err, append([]interface{}{places}, args...)...)
+	}
+	return d, nil
+}
+
+// DecFormat rounds a decimal to the provided number of decimal places and
+// formats it. The arguments are the same as for DecRound. The result can be
+// piped to Commas:
+//		{{ .Total | DecFormat 2 | Commas }}
+func DecFormat(places int, args ...interface{}) (string, error) {
+	d, err := roundArgs(places, args)
+	if err != nil {
+		return "", funcError("DecFormat", err, append([]interface{}{places}, args...)...)
+	}
+	return d.String(), nil
+}
+
+// Shift multiplies a number by 10^places exactly. A negative number of places
+// divides it. The number is converted as by Dec, and trailing zeros after the
+// decimal point are removed from the result. The number of places must not
+// exceed MaxDecimalPlaces.
+//		{{ Shift 2 "1.2345" }} -> 123.45
+//		{{ Shift -3 1500 }} -> 1.5
+func Shift(places int, v interface{}) (Decimal, error) {
+	d, err := toDecimal(v)
+	if err != nil {
+		return Decimal{}, funcError("Shift", err, places, v)
+	}
+	d, err = d.shift(places)
+	return d, funcError("Shift", err, places, v)
+}
+
+// ScaleDecimals converts an integer amount of base units, such as cents, wei
+// or satoshis, into display units with the provided number of decimals. It is
+// the same as Shift with a negated number of places.
+//		{{ .Balance | ScaleDecimals 18 }} -> 1.5
+//		{{ .Balance | ScaleDecimals 18 | DecFormat 4 | Commas }}
+func ScaleDecimals(decimals int, v interface{}) (Decimal, error) {
+	d, err := toDecimal(v)
+	if err != nil {
+		return Decimal{}, funcError("ScaleDecimals", err, decimals, v)
+	}
+	d, err = d.shift(-decimals)
+	return d, funcError("ScaleDecimals", err, decimals, v)
+}
+
+// shift multiplies d by 10^places and removes trailing zeros after the decimal
+// point.
+func (d Decimal) shift(places int) (Decimal, error) {
+	if err := checkPlaces("places", places); err != nil {
+		return Decimal{}, err
+	}
+
+	u, scale := new(big.Int).Set(d.int()), d.scale-places
+	if scale < 0 {
+		return Decimal{unscaled: u, scale: scale}.normalize(), nil
+	}
+
+	ten, r := big.NewInt(10), new(big.Int)
+	for scale > 0 && u.Sign() != 0 {
+		q, _ := new(big.Int).QuoRem(u, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		u, scale = q, scale-1
+	}
+	if u.Sign() == 0 {
+		scale = 0
+	}
+	return Decimal{unscaled: u, scale: scale}, nil
+}
+
+func decRoundArgs(args []interface{}) (Decimal, string, error) {
+	var mode string
+	switch len(args) {
+	case 1:
+	case 2:
+		m, ok := args[0].(string)
+		if !ok {
+			return Decimal{}, "", errors.New("rounding mode must be a string")
+		}
+		mode = m
+	default:
+		return Decimal{}, "", fmt.Errorf("wrong number of arguments: want 1 or 2, got %d", len(args))
+	}
+
+	d, err := toDecimal(args[len(args)-1])
+	return d, mode, err
+}
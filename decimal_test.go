@@ -0,0 +1,227 @@
+package temple
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	texttmpl "text/template"
+)
+
+func TestDec(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "string", arg: "19.99", want: "19.99"},
+		{name: "negative string", arg: "-0.05", want: "-0.05"},
+		{name: "exponent", arg: "1.5e3", want: "1500"},
+		{name: "negative exponent", arg: "15e-3", want: "0.015"},
+		{name: "json.Number", arg: json.Number("12345678901234567890.12"), want: "12345678901234567890.12"},
+		{name: "int", arg: 42, want: "42"},
+		{name: "uint64", arg: uint64(18446744073709551615), want: "18446744073709551615"},
+		{name: "float", arg: 0.1, want: "0.1"},
+		{name: "float32", arg: float32(0.1), want: "0.1"},
+		{name: "Decimal", arg: Decimal{}, want: "0"},
+		{name: "big.Int", arg: bigInt("-123456789012345678901234567890"), want: "-123456789012345678901234567890"},
+		{name: "big.Float", arg: big.NewFloat(2.5), want: "2.5"},
+		{name: "big.Rat", arg: big.NewRat(-1, 8), want: "-0.125"},
+		{name: "big.Rat repeating", arg: big.NewRat(2, 3), want: "0." + strings.Repeat("6", 33) + "7"},
+		{name: "nil big.Int", arg: (*big.Int)(nil), wantErr: true},
+		{name: "infinite big.Float", arg: new(big.Float).SetInf(false), wantErr: true},
+		{name: "invalid string", arg: "1.2.3", wantErr: true},
+		{name: "empty string", arg: "", wantErr: true},
+		{name: "invalid type", arg: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Dec(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Dec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("Dec() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(interface{}, ...interface{}) (Decimal, error)
+		x    interface{}
+		vals []interface{}
+		want string
+	}{
+		{name: "DecAdd floats", fn: DecAdd, x: 0.1, vals: []interface{}{0.2}, want: "0.3"},
+		{name: "DecAdd mixed", fn: DecAdd, x: "10.50", vals: []interface{}{json.Number("0.25"), 1}, want: "11.75"},
+		{name: "DecSub", fn: DecSub, x: "10.00", vals: []interface{}{0.01}, want: "9.99"},
+		{name: "DecSub negative", fn: DecSub, x: 1, vals: []interface{}{"1.5"}, want: "-0.5"},
+		{name: "DecMul", fn: DecMul, x: "19.99", vals: []interface{}{3}, want: "59.97"},
+		{name: "DecMul scale", fn: DecMul, x: "0.1", vals: []interface{}{"0.1", "0.1"}, want: "0.001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(tt.x, tt.vals...)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, err := DecAdd(1, "abc")
+	var fe *FuncError
+	if !errors.As(err, &fe) || fe.Func != "DecAdd" || !errors.Is(err, ErrInvalidDecimal) {
+		t.Errorf("DecAdd() error = %v, want ErrInvalidDecimal for DecAdd", err)
+	}
+}
+
+func TestDecRound(t *testing.T) {
+	tests := []struct {
+		name    string
+		places  int
+		args    []interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "half-up default", places: 2, args: []interface{}{"2.345"}, want: "2.35"},
+		{name: "half-up negative", places: 2, args: []interface{}{"half-up", "-2.345"}, want: "-2.35"},
+		{name: "half-even down", places: 2, args: []interface{}{"half-even", "2.345"}, want: "2.34"},
+		{name: "half-even up", places: 2, args: []interface{}{"half-even", "2.355"}, want: "2.36"},
+		{name: "half-even above half", places: 2, args: []interface{}{"half-even", "2.3451"}, want: "2.35"},
+		{name: "half-even negative", places: 0, args: []interface{}{"half-even", "-2.5"}, want: "-2"},
+		{name: "below half", places: 1, args: []interface{}{"0.04"}, want: "0.0"},
+		{name: "pad", places: 2, args: []interface{}{"1.5"}, want: "1.50"},
+		{name: "integer", places: 0, args: []interface{}{"1234.5"}, want: "1235"},
+		{name: "unknown mode", places: 2, args: []interface{}{"ceiling", "1"}, wantErr: true},
+		{name: "negative places", places: -2, args: []interface{}{"1250"}, want: "1300"},
+		{name: "negative places half-even", places: -2, args: []interface{}{"half-even", "1250"}, want: "1200"},
+		{name: "toward-zero", places: 2, args: []interface{}{"toward-zero", "-2.349"}, want: "-2.34"},
+		{name: "no decimal", places: 2, args: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecRound(tt.places, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecRound() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("DecRound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func(int, interface{}) (Decimal, error)
+		places  int
+		arg     interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "Shift left", fn: Shift, places: 2, arg: "1.2345", want: "123.45"},
+		{name: "Shift integer", fn: Shift, places: 3, arg: 15, want: "15000"},
+		{name: "Shift right", fn: Shift, places: -3, arg: 1500, want: "1.5"},
+		{name: "Shift zero", fn: Shift, places: -2, arg: "0.00", want: "0"},
+		{name: "Shift invalid", fn: Shift, places: 1, arg: "abc", wantErr: true},
+		{name: "ScaleDecimals cents", fn: ScaleDecimals, places: 2, arg: 12345, want: "123.45"},
+		{name: "ScaleDecimals wei", fn: ScaleDecimals, places: 18, arg: bigInt("1500000000000000000"), want: "1.5"},
+		{name: "ScaleDecimals small", fn: ScaleDecimals, places: 18, arg: json.Number("1"), want: "0.000000000000000001"},
+		{name: "ScaleDecimals large", fn: ScaleDecimals, places: 18, arg: bigInt("-123456789000000000000000000"), want: "-123456789"},
+		{name: "ScaleDecimals negative", fn: ScaleDecimals, places: -2, arg: 5, want: "500"},
+		{name: "Shift too many places", fn: Shift, places: 1000000000, arg: 1, wantErr: true},
+		{name: "ScaleDecimals too many places", fn: ScaleDecimals, places: -1000000000, arg: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(tt.places, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxDecimalPlaces(t *testing.T) {
+	huge := new(big.Float).SetMantExp(big.NewFloat(1), 1<<20)
+	tests := []struct {
+		name string
+		fn   func() (Decimal, error)
+	}{
+		{name: "Dec exponent", fn: func() (Decimal, error) { return Dec("1e999999999") }},
+		{name: "Dec negative exponent", fn: func() (Decimal, error) { return Dec("1e-10001") }},
+		{name: "Dec exponent out of int range", fn: func() (Decimal, error) { return Dec("1e99999999999999999999") }},
+		{name: "Dec big.Float", fn: func() (Decimal, error) { return Dec(huge) }},
+		{name: "DecRound", fn: func() (Decimal, error) { return DecRound(1000000000, "1.5") }},
+		{name: "DecRound negative", fn: func() (Decimal, error) { return DecRound(-1000000000, "1.5") }},
+		{name: "Shift", fn: func() (Decimal, error) { return Shift(MaxDecimalPlaces+1, 1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.fn(); !errors.Is(err, ErrOverflow) {
+				t.Errorf("error = %v, want ErrOverflow", err)
+			}
+		})
+	}
+
+	if d, err := Dec("1e10000"); err != nil || len(d.String()) != 10001 {
+		t.Errorf("Dec(1e10000) = %v, want 10001 digits", err)
+	}
+}
+
+func bigInt(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid integer " + s)
+	}
+	return i
+}
+
+func TestDecimalTemplates(t *testing.T) {
+	data := map[string]interface{}{
+		"Items": []interface{}{
+			map[string]interface{}{"Price": 0.1, "Qty": 3},
+			map[string]interface{}{"Price": json.Number("1234.565"), "Qty": 1000},
+		},
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{name: "float sum", tmpl: `{{ DecAdd 0.1 0.2 }}`, want: "0.3"},
+		{name: "commas", tmpl: `{{ DecMul "1234.5" 2 | DecFormat 2 | Commas }}`, want: "2,469.00"},
+		{name: "half-even", tmpl: `{{ Dec "0.125" | DecFormat 2 "half-even" }}`, want: "0.12"},
+		{
+			name: "invoice",
+			tmpl: `{{ $total := Dec 0 }}{{ range .Items }}{{ $total = DecMul .Price .Qty | DecAdd $total }}{{ end }}{{ $total | DecFormat 2 | Commas }}`,
+			want: "1,234,565.30",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := texttmpl.Must(texttmpl.New("").Funcs(DecimalFuncs.Text()).Funcs(StringsFuncs.Text()).Parse(tt.tmpl))
+
+			var b strings.Builder
+			if err := tmpl.Execute(&b, data); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package temple
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Errors reported by the template functions. They are returned wrapped in a
+// *FuncError, so errors.Is must be used to compare them.
+var (
+	ErrDivisionByZero = errors.New("division by zero")
+	ErrInvalidType    = errors.New("invalid type")
+	ErrEmpty          = errors.New("empty collection")
+	ErrNotNumeric     = errors.New("non numeric string")
+	ErrOutsideRoot    = errors.New("outside of root directory")
+	ErrInvalidDecimal = errors.New("invalid decimal")
+	ErrUnknownLocale  = errors.New("unknown locale")
+	ErrOverflow       = errors.New("numeric overflow")
+	ErrDomain         = errors.New("argument outside of domain")
+)
+
+// maxErrorArgLen is the maximum length of an argument in the message of a
+// FuncError. Longer arguments, such as template data, are truncated.
+const maxErrorArgLen = 32
+
+// FuncError is the error returned by every template function provided by
+// temple. Functions report failures through errors rather than panics, so the
+// template package includes the position of the failing action in its error.
+type FuncError struct {
+	// Func is the name of the function.
+	Func string
+	// Args contains the arguments the function was called with.
+	Args []interface{}
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *FuncError) Error() string {
+	args := make([]string, len(e.Args))
+	for i, a := range e.Args {
+		s := fmt.Sprintf("%v", a)
+		if _, ok := a.(string); ok {
+			s = fmt.Sprintf("%q", a)
+		}
+		if len(s) > maxErrorArgLen {
+			// Cut on a rune boundary so that the message stays valid UTF-8.
+			n := maxErrorArgLen - 3
+			for n > 0 && !utf8.RuneStart(s[n]) {
+				n--
+			}
+			s = s[:n] + "..."
+		}
+		args[i] = s
+	}
+	return fmt.Sprintf("%s(%s): %v", e.Func, strings.Join(args, ", "), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FuncError) Unwrap() error { return e.Err }
+
+// funcError wraps err in a *FuncError. If err is already a *FuncError, such as
+// when one function is implemented with another, its underlying error is used
+// so that the error names the function called by the template.
+func funcError(name string, err error, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	if fe, ok := err.(*FuncError); ok {
+		err = fe.Err
+	}
+	return &FuncError{Func: name, Args: args, Err: err}
+}
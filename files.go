@@ -0,0 +1,189 @@
+package temple
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileSystem provides template functions for reading files. All access is
+// restricted to files contained within the Root directory. Any path that
+// resolves to a location outside of Root, including through the use of
+// symlinks, is rejected.
+type FileSystem struct {
+	// Root is the directory that relative paths are resolved against.
+	Root string
+	// OnRead, if not nil, is called with the absolute path of every file
+	// that is read through ReadFile or ReadLines, or listed by Glob.
+	OnRead func(path string)
+}
+
+// NewFileSystem creates a FileSystem restricted to the provided root directory.
+func NewFileSystem(root string) *FileSystem {
+	return &FileSystem{Root: root}
+}
+
+// FuncMap returns a FuncMap containing the file functions bound to fs.
+func (fs *FileSystem) FuncMap() FuncMap {
+	return FuncMap{
+		"ReadFile":   fs.ReadFile,
+		"ReadLines":  fs.ReadLines,
+		"Glob":       fs.Glob,
+		"FileExists": fs.FileExists,
+	}
+}
+
+// ReadFile returns the contents of the named file.
+func (fs *FileSystem) ReadFile(name string) (string, error) {
+	s, err := fs.readFile(name)
+	return s, funcError("ReadFile", err, name)
+}
+
+func (fs *FileSystem) readFile(name string) (string, error) {
+	p, err := fs.resolve(name)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+
+	if fs.OnRead != nil {
+		fs.OnRead(p)
+	}
+
+	return string(b), nil
+}
+
+// ReadLines returns the lines of the named file. Line endings are stripped
+// from each line, and a trailing newline does not produce an empty line.
+func (fs *FileSystem) ReadLines(name string) ([]string, error) {
+	s, err := fs.readFile(name)
+	if err != nil {
+		return nil, funcError("ReadLines", err, name)
+	}
+
+	if s == "" {
+		return []string{}, nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	return lines, nil
+}
+
+// Glob returns the names of all files matching the pattern. The pattern syntax
+// is the same as in filepath.Match. The returned names are relative to the
+// root directory so that they can be passed directly to ReadFile. Matches that
+// resolve to a location outside of the root directory are omitted.
+func (fs *FileSystem) Glob(pattern string) ([]string, error) {
+	root, err := fs.root()
+	if err != nil {
+		return nil, funcError("Glob", err, pattern)
+	}
+
+	p := pattern
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(root, p)
+	}
+	if !within(root, filepath.Clean(p)) {
+		return nil, funcError("Glob", ErrOutsideRoot, pattern)
+	}
+
+	matches, err := filepath.Glob(p)
+	if err != nil {
+		return nil, funcError("Glob", err, pattern)
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		p, err := fs.resolve(m)
+		if err != nil {
+			continue
+		}
+		if fs.OnRead != nil {
+			fs.OnRead(p)
+		}
+
+		rel, err := filepath.Rel(root, m)
+		if err != nil {
+			return nil, funcError("Glob", err, pattern)
+		}
+		names = append(names, filepath.ToSlash(rel))
+	}
+	return names, nil
+}
+
+// FileExists determines if the named file exists. An error is only returned
+// if the name refers to a location outside of the root directory.
+func (fs *FileSystem) FileExists(name string) (bool, error) {
+	p, err := fs.resolve(name)
+	if err != nil {
+		return false, funcError("FileExists", err, name)
+	}
+
+	_, err = os.Stat(p)
+	return err == nil, nil
+}
+
+// root returns the absolute path of the root directory with all symlinks
+// evaluated.
+func (fs *FileSystem) root() (string, error) {
+	r := fs.Root
+	if r == "" {
+		r = "."
+	}
+
+	r, err := filepath.Abs(r)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.EvalSymlinks(r)
+}
+
+// resolve converts the provided name to an absolute path and ensures that it
+// is contained within the root directory. Relative names are resolved against
+// the root directory.
+func (fs *FileSystem) resolve(name string) (string, error) {
+	root, err := fs.root()
+	if err != nil {
+		return "", err
+	}
+
+	p := name
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(root, p)
+	}
+	p = filepath.Clean(p)
+
+	if !within(root, p) {
+		return "", ErrOutsideRoot
+	}
+
+	// The file may not exist, in which case there is no symlink to escape
+	// through and the lexical check above is sufficient.
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		p = resolved
+		if !within(root, p) {
+			return "", ErrOutsideRoot
+		}
+	}
+
+	return p, nil
+}
+
+// within determines if the path p is contained within the directory dir. Both
+// paths must be absolute and clean.
+func within(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,168 @@
+package temple
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTestFileSystem(t *testing.T) (*FileSystem, func()) {
+	dir, err := ioutil.TempDir("", "temple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := filepath.Join(dir, "root")
+	files := map[string]string{
+		filepath.Join(root, "a.txt"):        "line 1\r\nline 2\n",
+		filepath.Join(root, "sub", "b.sql"): "SELECT 1;",
+		filepath.Join(dir, "secret.txt"):    "secret",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return NewFileSystem(root), func() { os.RemoveAll(dir) }
+}
+
+func TestFileSystem_ReadFile(t *testing.T) {
+	fs, cleanup := newTestFileSystem(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Root file",
+			file: "a.txt",
+			want: "line 1\r\nline 2\n",
+		},
+		{
+			name: "Nested file",
+			file: "sub/b.sql",
+			want: "SELECT 1;",
+		},
+		{
+			name: "Clean path inside root",
+			file: "sub/../a.txt",
+			want: "line 1\r\nline 2\n",
+		},
+		{
+			name:    "Parent directory",
+			file:    "../secret.txt",
+			wantErr: true,
+		},
+		{
+			name:    "Absolute path outside root",
+			file:    "/etc/passwd",
+			wantErr: true,
+		},
+		{
+			name:    "Missing file",
+			file:    "missing.txt",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fs.ReadFile(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ReadFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileSystem_Symlink(t *testing.T) {
+	fs, cleanup := newTestFileSystem(t)
+	defer cleanup()
+
+	link := filepath.Join(fs.Root, "link.txt")
+	if err := os.Symlink(filepath.Join(fs.Root, "..", "secret.txt"), link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	if _, err := fs.ReadFile("link.txt"); err == nil {
+		t.Errorf("ReadFile() expected error for symlink outside of root")
+	}
+
+	got, err := fs.Glob("*.txt")
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	if want := []string{"a.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Glob() = %v, want %v", got, want)
+	}
+}
+
+func TestFileSystem_ReadLines(t *testing.T) {
+	fs, cleanup := newTestFileSystem(t)
+	defer cleanup()
+
+	got, err := fs.ReadLines("a.txt")
+	if err != nil {
+		t.Fatalf("ReadLines() error = %v", err)
+	}
+	if want := []string{"line 1", "line 2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadLines() = %v, want %v", got, want)
+	}
+
+	_, err = fs.ReadLines("missing.txt")
+	var fe *FuncError
+	if !errors.As(err, &fe) || fe.Func != "ReadLines" || errors.As(fe.Err, new(*FuncError)) {
+		t.Errorf("ReadLines() error = %#v, want a single FuncError for ReadLines", err)
+	}
+}
+
+func TestFileSystem_Glob(t *testing.T) {
+	fs, cleanup := newTestFileSystem(t)
+	defer cleanup()
+
+	var listed []string
+	fs.OnRead = func(path string) { listed = append(listed, filepath.Base(path)) }
+
+	got, err := fs.Glob("sub/*.sql")
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	if want := []string{"sub/b.sql"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Glob() = %v, want %v", got, want)
+	}
+	if want := []string{"b.sql"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("OnRead called with %v, want %v", listed, want)
+	}
+
+	if _, err := fs.Glob("../*.txt"); err == nil {
+		t.Errorf("Glob() expected error for pattern outside of root")
+	}
+}
+
+func TestFileSystem_FileExists(t *testing.T) {
+	fs, cleanup := newTestFileSystem(t)
+	defer cleanup()
+
+	if ok, err := fs.FileExists("a.txt"); !ok || err != nil {
+		t.Errorf("FileExists(a.txt) = %v, %v, want true, nil", ok, err)
+	}
+	if ok, err := fs.FileExists("missing.txt"); ok || err != nil {
+		t.Errorf("FileExists(missing.txt) = %v, %v, want false, nil", ok, err)
+	}
+	if _, err := fs.FileExists("../secret.txt"); err == nil {
+		t.Errorf("FileExists(../secret.txt) expected error")
+	}
+}
@@ -0,0 +1,129 @@
+package temple
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FuncFilter restricts the functions available to templates, such as when
+// rendering untrusted templates. Each entry of Allow and Deny is one of
+//		Name               a function name
+//		category:Name      every function in a registry category
+//		cap:Name           every function with a capability
+//
+// Categories and capabilities are found by looking up the implementation of
+// each function in the registry, so functions added under a different name,
+// such as by PrefixFuncMap, are still matched. If several registered functions
+// share an implementation, as closures created by the same function literal
+// do, a category or capability matches if it matches any of them. Functions
+// that are not in the registry only match by name.
+type FuncFilter struct {
+	// Allow lists the permitted functions. If empty, every function that is
+	// not denied is permitted.
+	Allow []string
+	// Deny lists the functions that are not permitted. Deny takes precedence
+	// over Allow.
+	Deny []string
+	// Registry is used to look up categories and capabilities. If nil,
+	// DefaultRegistry is used.
+	Registry *Registry
+}
+
+// Filter returns a copy of f containing only the permitted functions, along
+// with the sorted names of the removed functions. An error is returned if an
+// entry names an unknown category or capability.
+func (ff FuncFilter) Filter(f FuncMap) (FuncMap, []string, error) {
+	if err := ff.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	out := make(FuncMap, len(f))
+	var removed []string
+	for name, fn := range f {
+		if ff.permits(name, fn) {
+			out[name] = fn
+		} else {
+			removed = append(removed, name)
+		}
+	}
+
+	sort.Strings(removed)
+	return out, removed, nil
+}
+
+// Permits determines if the named function is permitted.
+func (ff FuncFilter) Permits(name string, fn interface{}) (bool, error) {
+	if err := ff.validate(); err != nil {
+		return false, err
+	}
+	return ff.permits(name, fn), nil
+}
+
+// FilterFuncMap is shorthand for FuncFilter{Allow: allow, Deny: deny}.Filter(f).
+func FilterFuncMap(f FuncMap, allow, deny []string) (FuncMap, []string, error) {
+	return FuncFilter{Allow: allow, Deny: deny}.Filter(f)
+}
+
+func (ff FuncFilter) registry() *Registry {
+	if ff.Registry != nil {
+		return ff.Registry
+	}
+	return DefaultRegistry
+}
+
+func (ff FuncFilter) validate() error {
+	categories := make(map[string]bool)
+	for _, c := range ff.registry().Categories() {
+		categories[c] = true
+	}
+
+	for _, entry := range append(append([]string(nil), ff.Allow...), ff.Deny...) {
+		switch {
+		case strings.HasPrefix(entry, "category:"):
+			if c := strings.TrimPrefix(entry, "category:"); !categories[c] {
+				return fmt.Errorf("temple: unknown category %q", c)
+			}
+		case strings.HasPrefix(entry, "cap:"):
+			if _, err := ParseCapability(strings.TrimPrefix(entry, "cap:")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ff FuncFilter) permits(name string, fn interface{}) bool {
+	infos := ff.registry().lookupImpl(fn)
+
+	if len(ff.Allow) > 0 && !matchFilter(ff.Allow, name, infos) {
+		return false
+	}
+	return !matchFilter(ff.Deny, name, infos)
+}
+
+// matchFilter determines if any of the filter entries matches the named
+// function, whose implementation is registered as infos. The validity of the
+// entries must already be checked.
+func matchFilter(entries []string, name string, infos []*Func) bool {
+	for _, entry := range entries {
+		switch {
+		case strings.HasPrefix(entry, "category:"):
+			for _, info := range infos {
+				if info.Category == strings.TrimPrefix(entry, "category:") {
+					return true
+				}
+			}
+		case strings.HasPrefix(entry, "cap:"):
+			c, _ := ParseCapability(strings.TrimPrefix(entry, "cap:"))
+			for _, info := range infos {
+				if info.Capabilities&c != 0 {
+					return true
+				}
+			}
+		case entry == name:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package temple
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuncFilter_Filter(t *testing.T) {
+	funcs := MergeFuncMaps(FuncMap{}, StringsFuncs, NewFileSystem(".").FuncMap(), FuncMap{"custom": ToUpper})
+
+	tests := []struct {
+		name    string
+		filter  FuncFilter
+		want    []string
+		removed []string
+		wantErr bool
+	}{
+		{
+			name:    "Deny capability",
+			filter:  FuncFilter{Deny: []string{"cap:file"}},
+			want:    []string{"Commas", "FormatMask", "IsNumeric", "Join", "custom"},
+			removed: []string{"FileExists", "Glob", "ReadFile", "ReadLines"},
+		},
+		{
+			name:    "Allow category",
+			filter:  FuncFilter{Allow: []string{"category:files"}},
+			want:    []string{"FileExists", "Glob", "ReadFile", "ReadLines"},
+			removed: []string{"Commas", "FormatMask", "IsNumeric", "Join", "custom"},
+		},
+		{
+			name:    "Deny takes precedence",
+			filter:  FuncFilter{Allow: []string{"Join", "ReadFile", "custom"}, Deny: []string{"ReadFile"}},
+			want:    []string{"Join", "custom"},
+			removed: []string{"Commas", "FileExists", "FormatMask", "Glob", "IsNumeric", "ReadFile", "ReadLines"},
+		},
+		{
+			name:    "Unknown capability",
+			filter:  FuncFilter{Deny: []string{"cap:disk"}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown category",
+			filter:  FuncFilter{Allow: []string{"category:text"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed, err := tt.filter.Filter(funcs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Filter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if names := sortedNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Filter() = %v, want %v", names, tt.want)
+			}
+			if !reflect.DeepEqual(removed, tt.removed) {
+				t.Errorf("Filter() removed = %v, want %v", removed, tt.removed)
+			}
+		})
+	}
+}
+
+func TestFilterFuncMap_prefixed(t *testing.T) {
+	funcs := MergeFuncMaps(FuncMap{}, PrefixFuncMap("f_", FileFuncs), PrefixFuncMap("s_", StringsFuncs))
+
+	got, removed, err := FilterFuncMap(funcs, nil, []string{"cap:file"})
+	if err != nil {
+		t.Fatalf("FilterFuncMap() error = %v", err)
+	}
+	if want := []string{"f_FileExists", "f_Glob", "f_ReadFile", "f_ReadLines"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("FilterFuncMap() removed = %v, want %v", removed, want)
+	}
+	if _, ok := got["s_Join"]; !ok {
+		t.Errorf("FilterFuncMap() removed s_Join")
+	}
+}
+
+func TestCapability_String(t *testing.T) {
+	if got, want := (CapFile | CapNetwork).String(), "file|network"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
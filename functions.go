@@ -27,7 +27,8 @@ func (f *FuncMap) Clear() { *f = make(FuncMap) }
 
 // MergeFuncMaps combines multiple FuncMap structures. If the same
 // function name is found in multiple FuncMaps, then the last
-// occurence will appear in the resulting FuncMap.
+// occurence will appear in the resulting FuncMap. Use
+// MergeFuncMapsPolicy to detect or prevent these overrides.
 func MergeFuncMaps(base FuncMap, additions ...FuncMap) FuncMap {
 	if base == nil {
 		base = make(FuncMap)
@@ -47,56 +48,783 @@ func MergeFuncMaps(base FuncMap, additions ...FuncMap) FuncMap {
 
 // FullFuncMap merges all standard FuncMaps.
 func FullFuncMap() FuncMap {
-	return MergeFuncMaps(
-		StringsFuncs,
-		NumbersFuncs,
-		ConversionFuncs,
-		CollectionFuncs,
+	return DefaultRegistry.FuncMap(
+		CategoryStrings,
+		CategoryNumbers,
+		CategoryConversion,
+		CategoryCollection,
+		CategoryDecimal,
+		CategoryStatistics,
+		CategoryMath,
 	)
 }
 
 // StringsFuncs maps all string related functions provided
 // by temple.
-var StringsFuncs FuncMap = FuncMap{
-	"Commas":     Commas,
-	"IsNumeric":  IsNumeric,
-	"Join":       Join,
-	"FormatMask": FormatMask,
-}
+var StringsFuncs FuncMap = DefaultRegistry.FuncMap(CategoryStrings)
 
-// NumbersFuncs maps all number and math related functions
-// provided by temple.
-var NumbersFuncs FuncMap = FuncMap{
-	"Max":      Max,
-	"IntMax":   IntMax,
-	"FloatMax": FloatMax,
-	"IntMin":   IntMin,
-	"FloatMin": FloatMin,
-	"Ceil":     Ceil,
-	"Floor":    Floor,
-	"Mod":      Mod,
-	"Sum":      Sum,
-	"Diff":     Diff,
-	"Mul":      Mul,
-	"Div":      Div,
-}
+// NumbersFuncs maps all number related functions provided
+// by temple. Math functions are in MathFuncs.
+var NumbersFuncs FuncMap = DefaultRegistry.FuncMap(CategoryNumbers)
 
 // ConversionFuncs maps all type conversion related functions
 // provided by temple.
-var ConversionFuncs FuncMap = FuncMap{
-	"ToInt":     ToInt,
-	"ToFloat64": ToFloat64,
-	"ToString":  ToString,
-
-	"ToIntSlice":     ToIntSlice,
-	"ToFloat64Slice": ToFloat64Slice,
-	"ToStringSlice":  ToStringSlice,
-}
+var ConversionFuncs FuncMap = DefaultRegistry.FuncMap(CategoryConversion)
 
 // CollectionFuncs maps all type conversion related functions
 // provided by temple.
-var CollectionFuncs FuncMap = FuncMap{
-	"NewList":  NewList,
-	"NewSet":   NewSet,
-	"Contains": Contains,
+var CollectionFuncs FuncMap = DefaultRegistry.FuncMap(CategoryCollection)
+
+// DecimalFuncs maps all exact decimal arithmetic functions
+// provided by temple.
+var DecimalFuncs FuncMap = DefaultRegistry.FuncMap(CategoryDecimal)
+
+// StatisticsFuncs maps all statistics functions provided by
+// temple.
+var StatisticsFuncs FuncMap = DefaultRegistry.FuncMap(CategoryStatistics)
+
+// MathFuncs maps all math functions provided by temple. The
+// functions return an error for arguments outside of their
+// domain instead of NaN or infinity.
+var MathFuncs FuncMap = DefaultRegistry.FuncMap(CategoryMath)
+
+// FileFuncs maps all file related functions provided by temple. These
+// functions are restricted to the current working directory. To use a
+// different directory, use NewFileSystem(root).FuncMap(). These functions are
+// not included in FullFuncMap.
+var FileFuncs FuncMap = DefaultRegistry.FuncMap(CategoryFiles)
+
+// DefaultRegistry contains every function provided by temple along with its
+// metadata. The standard FuncMaps are built from this registry.
+var DefaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	fs := NewFileSystem(".")
+
+	return NewRegistry().MustRegister(
+		// Strings
+		Func{
+			Name:        "Commas",
+			Fn:          Commas,
+			Category:    CategoryStrings,
+			Description: "Adds a comma after every three digits to the left of the decimal point of a number or numeric string.",
+			Examples:    []string{`{{ Commas "1234567.89" }} -> 1,234,567.89`},
+		},
+		Func{
+			Name:        "IsNumeric",
+			Fn:          IsNumeric,
+			Category:    CategoryStrings,
+			Description: "Determines if a string is a valid number. Commas are allowed to the left of the decimal point.",
+			Examples:    []string{`{{ IsNumeric "-1,234.5" }} -> true`},
+		},
+		Func{
+			Name:        "Join",
+			Fn:          Join,
+			Category:    CategoryStrings,
+			Description: "Joins the elements of a list with a separator. The elements are converted to strings.",
+			Examples:    []string{`{{ Join ", " (NewList "a" "b") }} -> a, b`},
+		},
+		Func{
+			Name:        "FormatMask",
+			Fn:          FormatMask,
+			Category:    CategoryStrings,
+			Description: "Replaces each '#' in a mask with the next rune of a string. A '#' preceded by '\\' is kept literally.",
+			Examples:    []string{"{{ FormatMask `(###) ###-####` \"5551234567\" }} -> (555) 123-4567"},
+		},
+
+		// Numbers
+		Func{
+			Name:        "Max",
+			Fn:          Max,
+			Category:    CategoryNumbers,
+			Description: "Returns the largest of the provided numbers, or of the numbers in a list. The result is a float if any number is a float.",
+			Examples:    []string{`{{ Max 1 5 3 }} -> 5`, `{{ Max 1 2.5 }} -> 2.5`},
+		},
+		Func{
+			Name:        "Min",
+			Fn:          Min,
+			Category:    CategoryNumbers,
+			Description: "Returns the smallest of the provided numbers, or of the numbers in a list. The result is a float if any number is a float.",
+			Examples:    []string{`{{ Min 4 1 3 }} -> 1`, `{{ Min 1.5 2 }} -> 1.5`},
+		},
+		Func{
+			Name:        "IntMax",
+			Fn:          IntMax,
+			Category:    CategoryNumbers,
+			Description: "Returns the largest of the provided values after converting them to ints.",
+			Examples:    []string{`{{ IntMax 1 5 3 }} -> 5`},
+		},
+		Func{
+			Name:        "FloatMax",
+			Fn:          FloatMax,
+			Category:    CategoryNumbers,
+			Description: "Returns the largest of the provided values after converting them to float64s.",
+			Examples:    []string{`{{ FloatMax 1.5 2.5 }} -> 2.5`},
+		},
+		Func{
+			Name:        "IntMin",
+			Fn:          IntMin,
+			Category:    CategoryNumbers,
+			Description: "Returns the smallest of the provided values after converting them to ints.",
+			Examples:    []string{`{{ IntMin 1 5 3 }} -> 1`},
+		},
+		Func{
+			Name:        "FloatMin",
+			Fn:          FloatMin,
+			Category:    CategoryNumbers,
+			Description: "Returns the smallest of the provided values after converting them to float64s.",
+			Examples:    []string{`{{ FloatMin 1.5 2.5 }} -> 1.5`},
+		},
+		Func{
+			Name:        "Ceil",
+			Fn:          ceilNumber,
+			Category:    CategoryNumbers,
+			Description: "Returns the least integer value greater than or equal to a number.",
+			Examples:    []string{`{{ Ceil 1.2 }} -> 2`},
+		},
+		Func{
+			Name:        "Floor",
+			Fn:          floorNumber,
+			Category:    CategoryNumbers,
+			Description: "Returns the greatest integer value less than or equal to a number.",
+			Examples:    []string{`{{ Floor 1.8 }} -> 1`},
+		},
+		Func{
+			Name:        "Mod",
+			Fn:          modNumbers,
+			Category:    CategoryNumbers,
+			Description: "Returns the floating point remainder of x/y. Dividing by zero is an error.",
+			Examples:    []string{`{{ Mod 7 2 }} -> 1`},
+		},
+		Func{
+			Name:        "Sum",
+			Fn:          sumNumbers,
+			Category:    CategoryNumbers,
+			Description: "Adds the provided numbers.",
+			Examples:    []string{`{{ Sum 1 2 3 }} -> 6`},
+		},
+		Func{
+			Name:        "Diff",
+			Fn:          diffNumbers,
+			Category:    CategoryNumbers,
+			Description: "Subtracts the remaining numbers from the first.",
+			Examples:    []string{`{{ Diff 10 2 3 }} -> 5`},
+		},
+		Func{
+			Name:        "Mul",
+			Fn:          mulNumbers,
+			Category:    CategoryNumbers,
+			Description: "Multiplies the provided numbers.",
+			Examples:    []string{`{{ Mul 2 3 }} -> 6`},
+		},
+		Func{
+			Name:        "Div",
+			Fn:          divNumbers,
+			Category:    CategoryNumbers,
+			Description: "Divides the first number by each of the remaining numbers. Dividing by zero is an error.",
+			Examples:    []string{`{{ Div 12 2 3 }} -> 2`},
+		},
+		Func{
+			Name:        "IntSum",
+			Fn:          IntSum,
+			Category:    CategoryNumbers,
+			Description: "Adds integers without converting them to floats. Overflowing an int64 is an error.",
+			Examples:    []string{`{{ IntSum 9007199254740993 1 }} -> 9007199254740994`},
+		},
+		Func{
+			Name:        "IntDiff",
+			Fn:          IntDiff,
+			Category:    CategoryNumbers,
+			Description: "Subtracts integers from the first one without converting them to floats. Overflowing an int64 is an error.",
+			Examples:    []string{`{{ IntDiff 10 3 2 }} -> 5`},
+		},
+		Func{
+			Name:        "IntMul",
+			Fn:          IntMul,
+			Category:    CategoryNumbers,
+			Description: "Multiplies integers without converting them to floats. Overflowing an int64 is an error.",
+			Examples:    []string{`{{ IntMul 3 4 5 }} -> 60`},
+		},
+		Func{
+			Name:        "IntDiv",
+			Fn:          IntDiv,
+			Category:    CategoryNumbers,
+			Description: "Returns the integer quotient of x/y, truncated toward zero. Dividing by zero is an error.",
+			Examples:    []string{`{{ IntDiv 7 2 }} -> 3`, `{{ IntDiv -7 2 }} -> -3`},
+		},
+		Func{
+			Name:        "IntMod",
+			Fn:          IntMod,
+			Category:    CategoryNumbers,
+			Description: "Returns the integer remainder of x/y, which has the sign of x. Dividing by zero is an error.",
+			Examples:    []string{`{{ IntMod 7 2 }} -> 1`, `{{ IntMod -7 2 }} -> -1`},
+		},
+		Func{
+			Name:        "IntPow",
+			Fn:          IntPow,
+			Category:    CategoryNumbers,
+			Description: "Raises an integer to a non-negative integer power. Overflowing an int64 is an error.",
+			Examples:    []string{`{{ IntPow 2 10 }} -> 1024`},
+		},
+		Func{
+			Name:        "IntAbs",
+			Fn:          IntAbs,
+			Category:    CategoryNumbers,
+			Description: "Returns the absolute value of an integer.",
+			Examples:    []string{`{{ IntAbs -5 }} -> 5`},
+		},
+		Func{
+			Name:        "Round",
+			Fn:          Round,
+			Category:    CategoryNumbers,
+			Description: "Rounds a number to a number of decimal places. The mode is \"half-up\" (default), \"half-even\" or \"toward-zero\".",
+			Examples:    []string{`{{ Round 2 2.675 }} -> 2.68`, `{{ Round 0 "half-even" 2.5 }} -> 2`},
+		},
+		Func{
+			Name:        "Truncate",
+			Fn:          Truncate,
+			Category:    CategoryNumbers,
+			Description: "Removes the digits of a number after a number of decimal places.",
+			Examples:    []string{`{{ Truncate 2 2.679 }} -> 2.67`},
+		},
+		Func{
+			Name:        "RoundSig",
+			Fn:          RoundSig,
+			Category:    CategoryNumbers,
+			Description: "Rounds a number to a number of significant figures, optionally with a rounding mode.",
+			Examples:    []string{`{{ RoundSig 3 123456 }} -> 123000`},
+		},
+		Func{
+			Name:        "FormatFixed",
+			Fn:          FormatFixed,
+			Category:    CategoryNumbers,
+			Description: "Rounds a number like Round and formats it with exactly that many decimal places.",
+			Examples:    []string{`{{ FormatFixed 2 3 }} -> 3.00`, `{{ FormatFixed 2 1234.5 | Commas }} -> 1,234.50`},
+		},
+		Func{
+			Name:        "FormatNumber",
+			Fn:          FormatNumber,
+			Category:    CategoryNumbers,
+			Description: "Formats a number for a locale, optionally with a number of decimal places.",
+			Examples:    []string{`{{ FormatNumber "de-DE" 1234.5 }} -> 1.234,5`, `{{ FormatNumber "hi-IN" 2 1234567 }} -> 12,34,567.00`},
+		},
+		Func{
+			Name:        "FormatCurrency",
+			Fn:          FormatCurrency,
+			Category:    CategoryNumbers,
+			Description: "Formats an amount of a currency, given by its ISO 4217 code, for a locale.",
+			Examples:    []string{`{{ FormatCurrency "en-US" "USD" 1234.5 }} -> $1,234.50`},
+		},
+		Func{
+			Name:        "FormatPercent",
+			Fn:          FormatPercent,
+			Category:    CategoryNumbers,
+			Description: "Formats a ratio as a percentage for a locale, optionally with a number of decimal places.",
+			Examples:    []string{`{{ FormatPercent "en-US" 0.25 }} -> 25%`},
+		},
+		Func{
+			Name:        "HumanBytes",
+			Fn:          HumanBytes,
+			Category:    CategoryNumbers,
+			Description: "Formats a number of bytes with an IEC (default) or SI unit and an optional precision.",
+			Examples:    []string{`{{ HumanBytes 1572864 }} -> 1.5 MiB`, `{{ HumanBytes "si" 1572864 }} -> 1.6 MB`},
+		},
+		Func{
+			Name:        "HumanDuration",
+			Fn:          HumanDuration,
+			Category:    CategoryNumbers,
+			Description: "Formats a duration, string or number of seconds with its largest units, optionally limiting the number of units.",
+			Examples:    []string{`{{ HumanDuration 11520 }} -> 3h 12m`},
+		},
+		Func{
+			Name:        "CompactNumber",
+			Fn:          CompactNumber,
+			Category:    CategoryNumbers,
+			Description: "Abbreviates a number with a K, M, B, T or Q suffix and an optional precision.",
+			Examples:    []string{`{{ CompactNumber 1234 }} -> 1.2K`, `{{ CompactNumber 2 3456789 }} -> 3.46M`},
+		},
+		Func{
+			Name:        "Ordinal",
+			Fn:          Ordinal,
+			Category:    CategoryNumbers,
+			Description: "Returns an integer with its English ordinal suffix.",
+			Examples:    []string{`{{ Ordinal 22 }} -> 22nd`},
+		},
+		Func{
+			Name:        "NumberToWords",
+			Fn:          NumberToWords,
+			Category:    CategoryNumbers,
+			Description: "Spells out a number in English, with cents written as a fraction of 100.",
+			Examples:    []string{`{{ NumberToWords 1234.5 }} -> one thousand two hundred thirty-four and 50/100`},
+		},
+
+		// Conversion
+		Func{
+			Name:        "ToInt",
+			Fn:          ToInt,
+			Category:    CategoryConversion,
+			Description: "Converts a value to an int.",
+			Examples:    []string{`{{ ToInt "42" }} -> 42`},
+		},
+		Func{
+			Name:        "ToFloat64",
+			Fn:          ToFloat64,
+			Category:    CategoryConversion,
+			Description: "Converts a value to a float64.",
+			Examples:    []string{`{{ ToFloat64 "1.5" }} -> 1.5`},
+		},
+		Func{
+			Name:        "ToString",
+			Fn:          ToString,
+			Category:    CategoryConversion,
+			Description: "Converts a value to a string.",
+			Examples:    []string{`{{ ToString 42 }} -> 42`},
+		},
+		Func{
+			Name:        "ToIntSlice",
+			Fn:          ToIntSlice,
+			Category:    CategoryConversion,
+			Description: "Converts each element of a list to an int.",
+		},
+		Func{
+			Name:        "ToFloat64Slice",
+			Fn:          ToFloat64Slice,
+			Category:    CategoryConversion,
+			Description: "Converts each element of a list to a float64.",
+		},
+		Func{
+			Name:        "ToStringSlice",
+			Fn:          ToStringSlice,
+			Category:    CategoryConversion,
+			Description: "Converts each element of a list to a string.",
+		},
+
+		// Collection
+		Func{
+			Name:        "NewList",
+			Fn:          NewList,
+			Category:    CategoryCollection,
+			Description: "Creates a List containing the provided values.",
+			Examples:    []string{`{{ NewList 1 2 3 }} -> [1 2 3]`},
+		},
+		Func{
+			Name:        "NewSet",
+			Fn:          NewSet,
+			Category:    CategoryCollection,
+			Description: "Creates a Set containing the provided values.",
+		},
+		Func{
+			Name:        "Contains",
+			Fn:          Contains,
+			Category:    CategoryCollection,
+			Description: "Determines if a List or Set contains a value.",
+			Examples:    []string{`{{ Contains 2 (NewList 1 2 3) }} -> true`},
+		},
+
+		// Decimal
+		Func{
+			Name:        "Dec",
+			Fn:          Dec,
+			Category:    CategoryDecimal,
+			Description: "Converts a string, json.Number or number to an exact decimal.",
+			Examples:    []string{`{{ Dec "19.99" }} -> 19.99`},
+		},
+		Func{
+			Name:        "DecAdd",
+			Fn:          DecAdd,
+			Category:    CategoryDecimal,
+			Description: "Adds decimals without rounding errors.",
+			Examples:    []string{`{{ DecAdd 0.1 0.2 }} -> 0.3`},
+		},
+		Func{
+			Name:        "DecSub",
+			Fn:          DecSub,
+			Category:    CategoryDecimal,
+			Description: "Subtracts decimals from the first argument without rounding errors.",
+			Examples:    []string{`{{ DecSub "10.00" 0.01 }} -> 9.99`},
+		},
+		Func{
+			Name:        "DecMul",
+			Fn:          DecMul,
+			Category:    CategoryDecimal,
+			Description: "Multiplies decimals without rounding errors.",
+			Examples:    []string{`{{ DecMul "19.99" 3 }} -> 59.97`},
+		},
+		Func{
+			Name:        "DecRound",
+			Fn:          DecRound,
+			Category:    CategoryDecimal,
+			Description: "Rounds a decimal to a number of places. The mode is \"half-up\" (default) or \"half-even\".",
+			Examples:    []string{`{{ Dec "2.345" | DecRound 2 }} -> 2.35`, `{{ Dec "2.345" | DecRound 2 "half-even" }} -> 2.34`},
+		},
+		Func{
+			Name:        "DecFormat",
+			Fn:          DecFormat,
+			Category:    CategoryDecimal,
+			Description: "Rounds a decimal like DecRound and returns it as a string.",
+			Examples:    []string{`{{ DecMul "1234.5" 2 | DecFormat 2 | Commas }} -> 2,469.00`},
+		},
+		Func{
+			Name:        "Shift",
+			Fn:          Shift,
+			Category:    CategoryDecimal,
+			Description: "Multiplies a number by 10^places exactly. A negative number of places divides it.",
+			Examples:    []string{`{{ Shift 2 "1.2345" }} -> 123.45`, `{{ Shift -3 1500 }} -> 1.5`},
+		},
+		Func{
+			Name:        "ScaleDecimals",
+			Fn:          ScaleDecimals,
+			Category:    CategoryDecimal,
+			Description: "Converts an amount of base units, such as cents or wei, into display units with the provided number of decimals.",
+			Examples:    []string{`{{ ScaleDecimals 2 12345 }} -> 123.45`, `{{ ScaleDecimals 18 "1500000000000000000" }} -> 1.5`},
+		},
+
+		// Statistics
+		Func{
+			Name:        "By",
+			Fn:          By,
+			Category:    CategoryStatistics,
+			Description: "Returns the values of a field, or a dotted path of fields, of each map or struct in a collection.",
+			Examples:    []string{`{{ Mean (By "Price" .Items) }}`},
+		},
+		Func{
+			Name:        "Count",
+			Fn:          Count,
+			Category:    CategoryStatistics,
+			Description: "Returns the number of numbers, flattening collections.",
+			Examples:    []string{`{{ Count (NewList 1 2 3) }} -> 3`},
+		},
+		Func{
+			Name:        "SumOf",
+			Fn:          SumOf,
+			Category:    CategoryStatistics,
+			Description: "Adds numbers, returning an int if all of them are integers.",
+			Examples:    []string{`{{ SumOf 1 2 3 }} -> 6`},
+		},
+		Func{
+			Name:        "Mean",
+			Fn:          Mean,
+			Category:    CategoryStatistics,
+			Description: "Returns the arithmetic mean of numbers.",
+			Examples:    []string{`{{ Mean 1 2 3 4 }} -> 2.5`},
+		},
+		Func{
+			Name:        "Median",
+			Fn:          Median,
+			Category:    CategoryStatistics,
+			Description: "Returns the median of numbers.",
+			Examples:    []string{`{{ Median 3 1 2 }} -> 2`},
+		},
+		Func{
+			Name:        "Mode",
+			Fn:          Mode,
+			Category:    CategoryStatistics,
+			Description: "Returns the most frequent number, or the smallest of the most frequent numbers.",
+			Examples:    []string{`{{ Mode 1 2 2 3 }} -> 2`},
+		},
+		Func{
+			Name:        "Variance",
+			Fn:          Variance,
+			Category:    CategoryStatistics,
+			Description: "Returns the population variance of numbers.",
+			Examples:    []string{`{{ Variance 2 4 4 4 5 5 7 9 }} -> 4`},
+		},
+		Func{
+			Name:        "StdDev",
+			Fn:          StdDev,
+			Category:    CategoryStatistics,
+			Description: "Returns the population standard deviation of numbers.",
+			Examples:    []string{`{{ StdDev 2 4 4 4 5 5 7 9 }} -> 2`},
+		},
+		Func{
+			Name:        "Percentile",
+			Fn:          Percentile,
+			Category:    CategoryStatistics,
+			Description: "Returns a percentile between 0 and 100 of numbers, interpolating between them.",
+			Examples:    []string{`{{ Percentile 90 .Latencies }}`},
+		},
+
+		// Math
+		Func{
+			Name:        "Abs",
+			Fn:          absNumber,
+			Category:    CategoryMath,
+			Description: "Returns the absolute value of a number.",
+			Examples:    []string{`{{ Abs -2.5 }} -> 2.5`},
+		},
+		Func{
+			Name:        "Pow",
+			Fn:          Pow,
+			Category:    CategoryMath,
+			Description: "Returns x raised to the power y. A negative x requires an integer y, and zero cannot be raised to a negative power.",
+			Examples:    []string{`{{ Pow 2 10 }} -> 1024`, `{{ Pow 2 0.5 }} -> 1.4142135623730951`},
+		},
+		Func{
+			Name:        "Sqrt",
+			Fn:          Sqrt,
+			Category:    CategoryMath,
+			Description: "Returns the square root of a non-negative number.",
+			Examples:    []string{`{{ Sqrt 16 }} -> 4`},
+		},
+		Func{
+			Name:        "Log",
+			Fn:          Log,
+			Category:    CategoryMath,
+			Description: "Returns the natural logarithm of a positive number, or its logarithm in the base preceding it.",
+			Examples:    []string{`{{ Log 1 }} -> 0`, `{{ Log 10 1000 }} -> 3`},
+		},
+		Func{
+			Name:        "Exp",
+			Fn:          Exp,
+			Category:    CategoryMath,
+			Description: "Returns e raised to the power x.",
+			Examples:    []string{`{{ Exp 0 }} -> 1`},
+		},
+		Func{
+			Name:        "Hypot",
+			Fn:          Hypot,
+			Category:    CategoryMath,
+			Description: "Returns the square root of x*x + y*y.",
+			Examples:    []string{`{{ Hypot 3 4 }} -> 5`},
+		},
+		Func{
+			Name:        "Sign",
+			Fn:          Sign,
+			Category:    CategoryMath,
+			Description: "Returns -1, 0 or 1 depending on whether a number is negative, zero or positive.",
+			Examples:    []string{`{{ Sign -2.5 }} -> -1`},
+		},
+		Func{
+			Name:        "Larger",
+			Fn:          Larger,
+			Category:    CategoryMath,
+			Description: "Returns the larger of two numbers of any kind. The result is a float if either number is a float.",
+			Examples:    []string{`{{ Larger 3 2.5 }} -> 3`},
+		},
+		Func{
+			Name:        "Smaller",
+			Fn:          Smaller,
+			Category:    CategoryMath,
+			Description: "Returns the smaller of two numbers of any kind. The result is a float if either number is a float.",
+			Examples:    []string{`{{ Smaller 3 2.5 }} -> 2.5`},
+		},
+		Func{
+			Name:        "Clamp",
+			Fn:          Clamp,
+			Category:    CategoryMath,
+			Description: "Limits the last number to the range from the first number to the second.",
+			Examples:    []string{`{{ Clamp 0 100 120 }} -> 100`, `{{ Clamp 0 100 -5 }} -> 0`},
+		},
+		Func{
+			Name:        "GCD",
+			Fn:          GCD,
+			Category:    CategoryMath,
+			Description: "Returns the greatest common divisor of integers.",
+			Examples:    []string{`{{ GCD 12 18 }} -> 6`},
+		},
+		Func{
+			Name:        "LCM",
+			Fn:          LCM,
+			Category:    CategoryMath,
+			Description: "Returns the least common multiple of integers.",
+			Examples:    []string{`{{ LCM 4 6 }} -> 12`},
+		},
+
+		// Files
+		Func{
+			Name:         "ReadFile",
+			Fn:           fs.ReadFile,
+			Category:     CategoryFiles,
+			Capabilities: CapFile,
+			Description:  "Returns the contents of a file.",
+			Examples:     []string{`{{ ReadFile "LICENSE" }}`},
+		},
+		Func{
+			Name:         "ReadLines",
+			Fn:           fs.ReadLines,
+			Category:     CategoryFiles,
+			Capabilities: CapFile,
+			Description:  "Returns the lines of a file without their line endings.",
+			Examples:     []string{`{{ range ReadLines "hosts.txt" }}{{ . }}{{ end }}`},
+		},
+		Func{
+			Name:         "Glob",
+			Fn:           fs.Glob,
+			Category:     CategoryFiles,
+			Capabilities: CapFile,
+			Description:  "Returns the names of the files matching a pattern.",
+			Examples:     []string{`{{ range Glob "sql/*.sql" }}{{ ReadFile . }}{{ end }}`},
+		},
+		Func{
+			Name:         "FileExists",
+			Fn:           fs.FileExists,
+			Category:     CategoryFiles,
+			Capabilities: CapFile,
+			Description:  "Determines if a file exists.",
+			Examples:     []string{`{{ if FileExists "NOTICE" }}{{ ReadFile "NOTICE" }}{{ end }}`},
+		},
+
+		// Sprig compatibility
+		Func{
+			Name:        "add",
+			Fn:          sprigAdd,
+			Category:    CategorySprig,
+			Description: "Adds integers. The arguments are converted to int64.",
+			Examples:    []string{`{{ add 1 2 3 }} -> 6`},
+		},
+		Func{
+			Name:        "add1",
+			Fn:          sprigAdd1,
+			Category:    CategorySprig,
+			Description: "Increments an integer by 1.",
+			Examples:    []string{`{{ add1 5 }} -> 6`},
+		},
+		Func{
+			Name:        "sub",
+			Fn:          sprigSub,
+			Category:    CategorySprig,
+			Description: "Subtracts the second integer from the first.",
+			Examples:    []string{`{{ sub 5 2 }} -> 3`},
+		},
+		Func{
+			Name:        "mul",
+			Fn:          sprigMul,
+			Category:    CategorySprig,
+			Description: "Multiplies integers.",
+			Examples:    []string{`{{ mul 2 3 4 }} -> 24`},
+		},
+		Func{
+			Name:        "div",
+			Fn:          sprigDiv,
+			Category:    CategorySprig,
+			Description: "Performs integer division.",
+			Examples:    []string{`{{ div 7 2 }} -> 3`},
+		},
+		Func{
+			Name:        "mod",
+			Fn:          sprigMod,
+			Category:    CategorySprig,
+			Description: "Returns the remainder of integer division.",
+			Examples:    []string{`{{ mod 7 2 }} -> 1`},
+		},
+		Func{
+			Name:        "max",
+			Fn:          sprigMax,
+			Category:    CategorySprig,
+			Description: "Returns the largest of a list of integers.",
+			Examples:    []string{`{{ max 1 5 3 }} -> 5`},
+		},
+		Func{
+			Name:        "min",
+			Fn:          sprigMin,
+			Category:    CategorySprig,
+			Description: "Returns the smallest of a list of integers.",
+			Examples:    []string{`{{ min 4 1 3 }} -> 1`},
+		},
+		Func{
+			Name:        "maxf",
+			Fn:          sprigMaxf,
+			Category:    CategorySprig,
+			Description: "Returns the largest of a list of floats.",
+			Examples:    []string{`{{ maxf 1.5 2.5 }} -> 2.5`},
+		},
+		Func{
+			Name:        "minf",
+			Fn:          sprigMinf,
+			Category:    CategorySprig,
+			Description: "Returns the smallest of a list of floats.",
+			Examples:    []string{`{{ minf 1.5 2.5 }} -> 1.5`},
+		},
+		Func{
+			Name:        "floor",
+			Fn:          sprigFloor,
+			Category:    CategorySprig,
+			Description: "Returns the greatest integer value less than or equal to a number.",
+			Examples:    []string{`{{ floor 1.5 }} -> 1`},
+		},
+		Func{
+			Name:        "ceil",
+			Fn:          sprigCeil,
+			Category:    CategorySprig,
+			Description: "Returns the least integer value greater than or equal to a number.",
+			Examples:    []string{`{{ ceil 1.5 }} -> 2`},
+		},
+		Func{
+			Name:        "upper",
+			Fn:          ToUpper,
+			Category:    CategorySprig,
+			Description: "Converts a string to uppercase.",
+			Examples:    []string{`{{ upper "abc" }} -> ABC`},
+		},
+		Func{
+			Name:        "lower",
+			Fn:          ToLower,
+			Category:    CategorySprig,
+			Description: "Converts a string to lowercase.",
+			Examples:    []string{`{{ lower "ABC" }} -> abc`},
+		},
+		Func{
+			Name:        "join",
+			Fn:          sprigJoin,
+			Category:    CategorySprig,
+			Description: "Joins the elements of a list with a separator. Nil elements are skipped.",
+			Examples:    []string{`{{ join ", " (list "a" "b") }} -> a, b`},
+		},
+		Func{
+			Name:        "toString",
+			Fn:          sprigString,
+			Category:    CategorySprig,
+			Description: "Converts a value to a string.",
+			Examples:    []string{`{{ toString 5 }} -> 5`},
+		},
+		Func{
+			Name:        "toStrings",
+			Fn:          sprigStrings,
+			Category:    CategorySprig,
+			Description: "Converts a list to a list of strings.",
+			Examples:    []string{`{{ toStrings (list 1 2) }} -> [1 2]`},
+		},
+		Func{
+			Name:        "int",
+			Fn:          sprigInt,
+			Category:    CategorySprig,
+			Description: "Converts a value to an int.",
+			Examples:    []string{`{{ int "5" }} -> 5`},
+		},
+		Func{
+			Name:        "int64",
+			Fn:          sprigInt64,
+			Category:    CategorySprig,
+			Description: "Converts a value to an int64.",
+			Examples:    []string{`{{ int64 "5" }} -> 5`},
+		},
+		Func{
+			Name:        "float64",
+			Fn:          sprigFloat64,
+			Category:    CategorySprig,
+			Description: "Converts a value to a float64.",
+			Examples:    []string{`{{ float64 "1.5" }} -> 1.5`},
+		},
+		Func{
+			Name:        "atoi",
+			Fn:          sprigAtoi,
+			Category:    CategorySprig,
+			Description: "Converts a string to an int. Invalid strings become 0.",
+			Examples:    []string{`{{ atoi "5" }} -> 5`},
+		},
+		Func{
+			Name:        "list",
+			Fn:          sprigList,
+			Category:    CategorySprig,
+			Description: "Creates a list from its arguments.",
+			Examples:    []string{`{{ list 1 2 3 }} -> [1 2 3]`},
+		},
+		Func{
+			Name:        "has",
+			Fn:          sprigHas,
+			Category:    CategorySprig,
+			Description: "Determines if a list contains a value. The value is the first argument.",
+			Examples:    []string{`{{ has 2 (list 1 2 3) }} -> true`},
+		},
+	)
 }
@@ -3,6 +3,7 @@ module github.com/mattmeyers/temple
 go 1.13
 
 require (
+	github.com/Masterminds/sprig/v3 v3.2.0
 	github.com/fsnotify/fsnotify v1.4.9
 	github.com/spf13/cast v1.3.1
 	golang.org/x/sys v0.0.0-20201009025420-dfb3f7c4e634 // indirect
@@ -0,0 +1,18 @@
+package temple_test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/templatetest"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	templatetest.Test(t, "testdata", templatetest.Options{
+		Funcs:  temple.FullFuncMap(),
+		Update: *update,
+	})
+}
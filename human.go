@@ -0,0 +1,382 @@
+package temple
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	siByteUnits  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
+	iecByteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"}
+	compactUnits = []string{"", "K", "M", "B", "T", "Q"}
+)
+
+// HumanBytes formats a number of bytes with a binary (IEC) or decimal (SI)
+// unit. The number is the last argument so that it can be piped. It may be
+// preceded by "iec" (the default) or "si", and by the number of decimal places
+// to round to, which defaults to 1. Trailing zeros are removed.
+//		{{ HumanBytes 1572864 }} -> 1.5 MiB
+//		{{ HumanBytes "si" 1572864 }} -> 1.6 MB
+//		{{ .Size | HumanBytes "si" 2 }} -> 1.57 MB
+func HumanBytes(args ...interface{}) (string, error) {
+	s, err := humanBytes(args)
+	return s, funcError("HumanBytes", err, args...)
+}
+
+func humanBytes(args []interface{}) (string, error) {
+	opts, v, err := humanArgs(args, 1, "iec", "si")
+	if err != nil {
+		return "", err
+	}
+	n, err := toNumber(v)
+	if err != nil {
+		return "", err
+	}
+
+	base, units := 1024.0, iecByteUnits
+	if opts.name == "si" {
+		base, units = 1000, siByteUnits
+	}
+	return scaleUnits(n.float64(), base, units, opts.precision, " "), nil
+}
+
+// CompactNumber abbreviates a number with a suffix for thousands (K), millions
+// (M), billions (B), trillions (T) and quadrillions (Q). The number may be
+// preceded by the number of decimal places to round to, which defaults to 1.
+// Trailing zeros are removed.
+//		{{ CompactNumber 1234 }} -> 1.2K
+//		{{ CompactNumber 2 3456789 }} -> 3.46M
+func CompactNumber(args ...interface{}) (string, error) {
+	s, err := compactNumber(args)
+	return s, funcError("CompactNumber", err, args...)
+}
+
+func compactNumber(args []interface{}) (string, error) {
+	opts, v, err := humanArgs(args, 1)
+	if err != nil {
+		return "", err
+	}
+	n, err := toNumber(v)
+	if err != nil {
+		return "", err
+	}
+	return scaleUnits(n.float64(), 1000, compactUnits, opts.precision, ""), nil
+}
+
+// scaleUnits divides f by base until it is smaller than base or the units run
+// out, and formats it with the unit.
+func scaleUnits(f, base float64, units []string, precision int, sep string) string {
+	neg := f < 0
+	f = math.Abs(f)
+
+	i := 0
+	for f >= base && i < len(units)-1 {
+		f /= base
+		i++
+	}
+
+	s := trimFraction(roundFloat(f, precision))
+	// Rounding may reach the next unit, such as 999.96 KiB rounding to 1000.
+	if s == trimFraction(roundFloat(base, precision)) && i < len(units)-1 {
+		i++
+		s = "1"
+	}
+
+	if neg {
+		s = "-" + s
+	}
+	if units[i] == "" {
+		return s
+	}
+	return s + sep + units[i]
+}
+
+// HumanDuration formats a duration with its largest units, such as "3h 12m".
+// The duration may be a time.Duration, a string accepted by
+// time.ParseDuration, or a number of seconds. It may be preceded by the
+// maximum number of units to show, which defaults to 2. Units with a count of
+// zero are omitted, and the remainder is truncated.
+//		{{ HumanDuration 11520 }} -> 3h 12m
+//		{{ HumanDuration 3 "26h3m4s" }} -> 1d 2h 3m
+func HumanDuration(args ...interface{}) (string, error) {
+	s, err := humanDuration(args)
+	return s, funcError("HumanDuration", err, args...)
+}
+
+var durationUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{24 * time.Hour, "d"},
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+	{time.Millisecond, "ms"},
+	{time.Microsecond, "µs"},
+	{time.Nanosecond, "ns"},
+}
+
+func humanDuration(args []interface{}) (string, error) {
+	opts, v, err := humanArgs(args, 2)
+	if err != nil {
+		return "", err
+	}
+	if opts.precision < 1 {
+		return "", fmt.Errorf("number of units must be positive, got %d", opts.precision)
+	}
+
+	d, err := toDuration(v)
+	if err != nil {
+		return "", err
+	}
+
+	neg := d < 0
+	if neg {
+		d = -d
+	}
+
+	// Units with a count of zero are skipped and not counted.
+	var parts []string
+	for _, u := range durationUnits {
+		if len(parts) == opts.precision || d == 0 {
+			break
+		}
+		if n := d / u.d; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, u.name))
+			d -= n * u.d
+		}
+	}
+
+	if len(parts) == 0 {
+		return "0s", nil
+	}
+	s := strings.Join(parts, " ")
+	if neg {
+		s = "-" + s
+	}
+	return s, nil
+}
+
+func toDuration(v interface{}) (time.Duration, error) {
+	switch v := v.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		return time.ParseDuration(v)
+	}
+
+	n, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	if !n.float {
+		return time.Duration(n.i) * time.Second, nil
+	}
+	return time.Duration(math.Round(n.f * float64(time.Second))), nil
+}
+
+// Ordinal returns an integer with its English ordinal suffix.
+//		{{ Ordinal 1 }} -> 1st
+//		{{ Ordinal 22 }} -> 22nd
+//		{{ Ordinal 113 }} -> 113th
+func Ordinal(v interface{}) (string, error) {
+	n, err := toNumber(v)
+	if err == nil && n.float {
+		err = ErrInvalidType
+	}
+	if err != nil {
+		return "", funcError("Ordinal", err, v)
+	}
+
+	abs := n.i % 100
+	if abs < 0 {
+		abs = -abs
+	}
+
+	suffix := "th"
+	if abs < 11 || abs > 13 {
+		switch abs % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n.i, suffix), nil
+}
+
+var (
+	smallNumberWords = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	tensWords = []string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+	}
+	scaleWords = []string{
+		"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion",
+		"sextillion", "septillion", "octillion", "nonillion", "decillion",
+	}
+)
+
+// NumberToWords spells out a number in English, as on a check. The number is
+// rounded to cents, which are always written as a fraction of 100.
+//		{{ NumberToWords 42 }} -> forty-two and 00/100
+//		{{ NumberToWords 1234.5 }} -> one thousand two hundred thirty-four and 50/100
+func NumberToWords(v interface{}) (string, error) {
+	s, err := numberToWords(v)
+	return s, funcError("NumberToWords", err, v)
+}
+
+func numberToWords(v interface{}) (string, error) {
+	d, err := toDecimal(v)
+	if err != nil {
+		return "", err
+	}
+
+	d, err = d.Round(2, RoundHalfUp)
+	if err != nil {
+		return "", err
+	}
+
+	cents := new(big.Int)
+	whole, _ := new(big.Int).QuoRem(new(big.Int).Abs(d.int()), big.NewInt(100), cents)
+
+	words, err := integerWords(whole)
+	if err != nil {
+		return "", err
+	}
+	if d.Sign() < 0 {
+		words = "minus " + words
+	}
+	return words + fmt.Sprintf(" and %02d/100", cents.Int64()), nil
+}
+
+// integerWords spells out a non-negative integer.
+func integerWords(n *big.Int) (string, error) {
+	if n.Sign() == 0 {
+		return smallNumberWords[0], nil
+	}
+
+	// Split the number into groups of three digits, least significant first.
+	var groups []int
+	thousand := big.NewInt(1000)
+	for rest, g := new(big.Int).Set(n), new(big.Int); rest.Sign() > 0; {
+		rest.QuoRem(rest, thousand, g)
+		groups = append(groups, int(g.Int64()))
+	}
+	if len(groups) > len(scaleWords) {
+		return "", errors.New("number is too large")
+	}
+
+	var words []string
+	for i := len(groups) - 1; i >= 0; i-- {
+		if groups[i] == 0 {
+			continue
+		}
+		words = append(words, hundredWords(groups[i]))
+		if scaleWords[i] != "" {
+			words = append(words, scaleWords[i])
+		}
+	}
+	return strings.Join(words, " "), nil
+}
+
+// hundredWords spells out a number from 1 to 999.
+func hundredWords(n int) string {
+	var words []string
+	if n >= 100 {
+		words = append(words, smallNumberWords[n/100], "hundred")
+		n %= 100
+	}
+
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, smallNumberWords[n])
+	case n%10 == 0:
+		words = append(words, tensWords[n/10])
+	default:
+		words = append(words, tensWords[n/10]+"-"+smallNumberWords[n%10])
+	}
+	return strings.Join(words, " ")
+}
+
+// humanOptions are the options preceding the value of the human readable
+// formatting functions.
+type humanOptions struct {
+	name      string
+	precision int
+}
+
+// humanArgs splits the arguments into the options and the value, which is the
+// last argument. An integer option is the precision, which defaults to
+// precision. A string option must be one of names, the first of which is the
+// default.
+func humanArgs(args []interface{}, precision int, names ...string) (humanOptions, interface{}, error) {
+	if len(args) == 0 {
+		return humanOptions{}, nil, errors.New("missing value")
+	}
+
+	opts := humanOptions{precision: precision}
+	if len(names) > 0 {
+		opts.name = names[0]
+	}
+
+	var seenName, seenPrecision bool
+	for _, a := range args[:len(args)-1] {
+		if s, ok := a.(string); ok && len(names) > 0 && !seenName {
+			if !containsString(names, strings.ToLower(s)) {
+				return humanOptions{}, nil, fmt.Errorf("unknown option %q, want one of %s", s, strings.Join(names, ", "))
+			}
+			opts.name, seenName = strings.ToLower(s), true
+			continue
+		}
+
+		n, err := toNumber(a)
+		if err != nil || n.float || seenPrecision {
+			return humanOptions{}, nil, fmt.Errorf("invalid option %v", a)
+		}
+		opts.precision, seenPrecision = int(n.i), true
+	}
+	if opts.precision < 0 {
+		return humanOptions{}, nil, fmt.Errorf("negative precision %d", opts.precision)
+	}
+	return opts, args[len(args)-1], nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// roundFloat rounds f half-up to the provided number of decimal places and
+// formats it.
+func roundFloat(f float64, places int) string {
+	d, err := toDecimal(f)
+	if err == nil {
+		d, err = d.Round(places, RoundHalfUp)
+	}
+	if err != nil {
+		return fmt.Sprint(f)
+	}
+	return d.String()
+}
+
+// trimFraction removes the trailing zeros after a decimal point.
+func trimFraction(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
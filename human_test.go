@@ -0,0 +1,99 @@
+package temple
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanFormatters(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func(...interface{}) (string, error)
+		args    []interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "HumanBytes bytes", fn: HumanBytes, args: []interface{}{512}, want: "512 B"},
+		{name: "HumanBytes iec", fn: HumanBytes, args: []interface{}{1572864}, want: "1.5 MiB"},
+		{name: "HumanBytes si", fn: HumanBytes, args: []interface{}{"si", 1572864}, want: "1.6 MB"},
+		{name: "HumanBytes precision", fn: HumanBytes, args: []interface{}{"SI", 2, 1572864}, want: "1.57 MB"},
+		{name: "HumanBytes precision first", fn: HumanBytes, args: []interface{}{2, "si", 1572864}, want: "1.57 MB"},
+		{name: "HumanBytes trims zeros", fn: HumanBytes, args: []interface{}{1024}, want: "1 KiB"},
+		{name: "HumanBytes next unit", fn: HumanBytes, args: []interface{}{1048575}, want: "1 MiB"},
+		{name: "HumanBytes negative", fn: HumanBytes, args: []interface{}{-2048}, want: "-2 KiB"},
+		{name: "HumanBytes unknown system", fn: HumanBytes, args: []interface{}{"metric", 1}, wantErr: true},
+		{name: "HumanBytes no value", fn: HumanBytes, args: nil, wantErr: true},
+		{name: "CompactNumber small", fn: CompactNumber, args: []interface{}{999}, want: "999"},
+		{name: "CompactNumber thousands", fn: CompactNumber, args: []interface{}{1234}, want: "1.2K"},
+		{name: "CompactNumber millions", fn: CompactNumber, args: []interface{}{2, 3456789}, want: "3.46M"},
+		{name: "CompactNumber next unit", fn: CompactNumber, args: []interface{}{999999}, want: "1M"},
+		{name: "CompactNumber negative", fn: CompactNumber, args: []interface{}{-1500000000}, want: "-1.5B"},
+		{name: "CompactNumber negative precision", fn: CompactNumber, args: []interface{}{-1, 1}, wantErr: true},
+		{name: "HumanDuration seconds", fn: HumanDuration, args: []interface{}{11520}, want: "3h 12m"},
+		{name: "HumanDuration units", fn: HumanDuration, args: []interface{}{3, "26h3m4s"}, want: "1d 2h 3m"},
+		{name: "HumanDuration skips zero units", fn: HumanDuration, args: []interface{}{"24h5m"}, want: "1d 5m"},
+		{name: "HumanDuration Duration", fn: HumanDuration, args: []interface{}{1500 * time.Millisecond}, want: "1s 500ms"},
+		{name: "HumanDuration float seconds", fn: HumanDuration, args: []interface{}{0.25}, want: "250ms"},
+		{name: "HumanDuration negative", fn: HumanDuration, args: []interface{}{-90}, want: "-1m 30s"},
+		{name: "HumanDuration zero", fn: HumanDuration, args: []interface{}{0}, want: "0s"},
+		{name: "HumanDuration invalid", fn: HumanDuration, args: []interface{}{"soon"}, wantErr: true},
+		{name: "HumanDuration no units", fn: HumanDuration, args: []interface{}{0, 60}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	tests := map[interface{}]string{
+		0: "0th", 1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th",
+		21: "21st", 22: "22nd", 101: "101st", 111: "111th", 113: "113th", -1: "-1st", "42": "42nd",
+	}
+	for v, want := range tests {
+		if got, err := Ordinal(v); err != nil || got != want {
+			t.Errorf("Ordinal(%v) = %q, %v, want %q", v, got, err, want)
+		}
+	}
+
+	if _, err := Ordinal(1.5); err == nil {
+		t.Error("Ordinal() accepted a float")
+	}
+}
+
+func TestNumberToWords(t *testing.T) {
+	tests := []struct {
+		v       interface{}
+		want    string
+		wantErr bool
+	}{
+		{v: 0, want: "zero and 00/100"},
+		{v: 7, want: "seven and 00/100"},
+		{v: 42, want: "forty-two and 00/100"},
+		{v: 100, want: "one hundred and 00/100"},
+		{v: 1234.5, want: "one thousand two hundred thirty-four and 50/100"},
+		{v: "1000000.07", want: "one million and 07/100"},
+		{v: 2000019, want: "two million nineteen and 00/100"},
+		{v: -3, want: "minus three and 00/100"},
+		{v: 0.995, want: "one and 00/100"},
+		{v: "1e36", wantErr: true},
+		{v: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NumberToWords(tt.v)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NumberToWords(%v) error = %v, wantErr %v", tt.v, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NumberToWords(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package temple
+
+import (
+	"errors"
+	htmltmpl "html/template"
+	"reflect"
+	"runtime"
+	"strings"
+	texttmpl "text/template"
+)
+
+// MaxIncludeDepth is the maximum number of nested Include and Tpl calls
+// allowed while executing a template. Exceeding this depth, most likely
+// through a template including itself, results in an error.
+const MaxIncludeDepth = 100
+
+// tplName is the name given to templates parsed by Tpl.
+const tplName = "temple.Tpl"
+
+// nest runs an Include or Tpl call. It fails if more than MaxIncludeDepth
+// calls are nested, which it finds out by counting its own frames on the
+// stack. An execution runs on a single goroutine, so the depth is kept per
+// execution even when the same template set is executed concurrently.
+//
+// Functions bound to a limited execution are given its limiter. Other
+// functions fail when called during a limited execution, such as under a
+// different name than the one Limits replaces, rather than execute templates
+// without the limits.
+func nest(lim *limiter, call func() error) error {
+	if lim == nil && callDepth(runLimited) > 0 {
+		return errors.New("not bound to the limited execution")
+	}
+	if callDepth(nest) > MaxIncludeDepth {
+		return errors.New("maximum include depth exceeded")
+	}
+	return call()
+}
+
+// callDepth returns the number of calls to fn on the stack of the current
+// goroutine.
+func callDepth(fn interface{}) int {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+
+	pc := make([]uintptr, 256)
+	n := runtime.Callers(1, pc)
+	for n == len(pc) {
+		pc = make([]uintptr, 2*len(pc))
+		n = runtime.Callers(1, pc)
+	}
+
+	depth := 0
+	frames := runtime.CallersFrames(pc[:n])
+	for more := true; more; {
+		var f runtime.Frame
+		f, more = frames.Next()
+		if f.Function == name {
+			depth++
+		}
+	}
+	return depth
+}
+
+// TextIncludeFuncs returns a FuncMap containing the Include and Tpl functions
+// bound to the provided text/template set. Because these functions must be
+// known before parsing, they should be added to t before any templates are
+// parsed. For example
+//		t := template.New("base")
+//		t.Funcs(temple.TextIncludeFuncs(t).Text()).ParseFiles(files...)
+//
+// Include executes the named template associated with t and returns the
+// result as a string. Unlike the template action, the output can be piped:
+//		{{ Include "row" . | printf "%q" }}
+//
+// Tpl parses the provided string as a template and executes it with the
+// provided data. The string has access to all of the templates associated
+// with t.
+//		{{ Tpl .Greeting . }}
+//
+// When t is executed through Limits.ExecuteText, both functions are replaced
+// in the copy of t by functions executing the copy, so that they are limited
+// as well.
+func TextIncludeFuncs(t *texttmpl.Template) FuncMap {
+	return textIncludeFuncs(t, nil)
+}
+
+// includeProbe is passed as the data to Include and Tpl by Limits to find out
+// whether they are the functions of TextIncludeFuncs or HTMLIncludeFuncs.
+// Instead of executing a template, the functions mark the probe as found and
+// record the FuncMap passed to HTMLIncludeFuncs.
+type includeProbe struct {
+	found bool
+	funcs FuncMap
+}
+
+// textIncludeFuncs returns the functions of TextIncludeFuncs. If lim is not
+// nil, t is the copy executed by Limits, and the functions buffer their output
+// through lim and instrument the templates parsed by Tpl.
+func textIncludeFuncs(t *texttmpl.Template, lim *limiter) FuncMap {
+	return FuncMap{
+		"Include": func(name string, data interface{}) (string, error) {
+			if p, ok := data.(*includeProbe); ok {
+				p.found = true
+				return "", nil
+			}
+
+			var b strings.Builder
+			err := nest(lim, func() error {
+				return t.ExecuteTemplate(lim.buffer(&b), name, data)
+			})
+			if err != nil {
+				return "", funcError("Include", err, name, data)
+			}
+			return b.String(), nil
+		},
+		"Tpl": func(text string, data interface{}) (string, error) {
+			if p, ok := data.(*includeProbe); ok {
+				p.found = true
+				return "", nil
+			}
+
+			var b strings.Builder
+			err := nest(lim, func() error {
+				c, err := t.Clone()
+				if err != nil {
+					return err
+				}
+
+				tpl, err := c.New(tplName).Parse(text)
+				if err != nil {
+					return err
+				}
+				if lim != nil {
+					instrument(textTrees(tpl))
+				}
+				return tpl.Execute(lim.buffer(&b), data)
+			})
+			if err != nil {
+				return "", funcError("Tpl", err, text, data)
+			}
+			return b.String(), nil
+		},
+	}
+}
+
+// HTMLIncludeFuncs returns a FuncMap containing the Include and Tpl functions
+// bound to the provided html/template set. See TextIncludeFuncs for a
+// description of each function. Both functions return template.HTML as their
+// output has already been escaped.
+//
+// An html/template set cannot be cloned once it has been executed, so the
+// templates passed to Tpl are parsed into a new set. The FuncMap used to parse
+// t must be provided so that these templates have access to the same
+// functions. Functions in funcs take precedence over Include and Tpl.
+// Templates parsed by Tpl can still execute the templates associated with t by
+// using Include.
+func HTMLIncludeFuncs(t *htmltmpl.Template, funcs FuncMap) FuncMap {
+	return htmlIncludeFuncs(t, funcs, nil)
+}
+
+// htmlIncludeFuncs is like textIncludeFuncs for HTMLIncludeFuncs. If lim is
+// not nil, the functions of HTMLIncludeFuncs in funcs are replaced by the
+// returned functions in the sets parsed by Tpl.
+func htmlIncludeFuncs(t *htmltmpl.Template, funcs FuncMap, lim *limiter) FuncMap {
+	var f FuncMap
+	f = FuncMap{
+		"Include": func(name string, data interface{}) (htmltmpl.HTML, error) {
+			if p, ok := data.(*includeProbe); ok {
+				p.found, p.funcs = true, funcs
+				return "", nil
+			}
+
+			var b strings.Builder
+			err := nest(lim, func() error {
+				return t.ExecuteTemplate(lim.buffer(&b), name, data)
+			})
+			if err != nil {
+				return "", funcError("Include", err, name, data)
+			}
+			return htmltmpl.HTML(b.String()), nil
+		},
+		"Tpl": func(text string, data interface{}) (htmltmpl.HTML, error) {
+			if p, ok := data.(*includeProbe); ok {
+				p.found, p.funcs = true, funcs
+				return "", nil
+			}
+
+			var b strings.Builder
+			err := nest(lim, func() error {
+				tpl := htmltmpl.New(tplName).Funcs(f.HTML()).Funcs(funcs.HTML())
+				if lim != nil {
+					tpl = tpl.Funcs(rebindInclude(funcs, f).HTML()).Funcs(lim.funcs().HTML())
+				}
+
+				tpl, err := tpl.Parse(text)
+				if err != nil {
+					return err
+				}
+				if lim != nil {
+					instrument(htmlTrees(tpl))
+				}
+				return tpl.Execute(lim.buffer(&b), data)
+			})
+			if err != nil {
+				return "", funcError("Tpl", err, text, data)
+			}
+			return htmltmpl.HTML(b.String()), nil
+		},
+	}
+	return f
+}
+
+// rebindInclude returns the functions of funcs created by the same function
+// literals as the functions of bound, such as the Include function of
+// HTMLIncludeFuncs, replaced by the functions of bound.
+func rebindInclude(funcs, bound FuncMap) FuncMap {
+	f := make(FuncMap)
+	for name, fn := range funcs {
+		v := reflect.ValueOf(fn)
+		if v.Kind() != reflect.Func {
+			continue
+		}
+		for _, b := range bound {
+			if reflect.ValueOf(b).Pointer() == v.Pointer() {
+				f[name] = b
+			}
+		}
+	}
+	return f
+}
@@ -0,0 +1,124 @@
+package temple
+
+import (
+	"errors"
+	"fmt"
+	htmltmpl "html/template"
+	"strings"
+	"sync"
+	"testing"
+	texttmpl "text/template"
+	"time"
+)
+
+func TestTextIncludeFuncs(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		data    interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Include piped",
+			tmpl: `{{ define "row" }}a{{ . }}{{ end }}{{ Include "row" "b" | printf "%q" }}`,
+			want: `"ab"`,
+		},
+		{
+			name: "Tpl from data",
+			tmpl: `{{ define "name" }}World{{ end }}{{ Tpl .Greeting . }}`,
+			data: map[string]string{"Greeting": `Hello, {{ template "name" }}!`},
+			want: "Hello, World!",
+		},
+		{
+			name:    "Undefined template",
+			tmpl:    `{{ Include "missing" . }}`,
+			wantErr: true,
+		},
+		{
+			name:    "Infinite recursion",
+			tmpl:    `{{ define "loop" }}{{ Include "loop" . }}{{ end }}{{ Include "loop" . }}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := texttmpl.New("test")
+			tmpl = texttmpl.Must(tmpl.Funcs(TextIncludeFuncs(tmpl).Text()).Parse(tt.tmpl))
+
+			var b strings.Builder
+			err := tmpl.Execute(&b, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := b.String(); !tt.wantErr && got != tt.want {
+				t.Errorf("Execute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextIncludeFuncs_concurrent(t *testing.T) {
+	// Both executions wait for each other at a depth of 60 Include calls, so
+	// their depths would exceed MaxIncludeDepth if they were shared.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	both := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(both)
+	}()
+
+	funcs := FuncMap{
+		"Dec": func(i int) int { return i - 1 },
+		"Wait": func() (string, error) {
+			wg.Done()
+			select {
+			case <-both:
+				return "done", nil
+			case <-time.After(5 * time.Second):
+				return "", errors.New("the other execution did not reach the same depth")
+			}
+		},
+	}
+	tmpl := texttmpl.New("test")
+	tmpl = texttmpl.Must(tmpl.Funcs(TextIncludeFuncs(tmpl).Text()).Funcs(funcs.Text()).Parse(
+		`{{ define "r" }}{{ if . }}{{ Include "r" (Dec .) }}{{ else }}{{ Wait }}{{ end }}{{ end }}{{ Include "r" . }}`,
+	))
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			var b strings.Builder
+			err := tmpl.Execute(&b, 59)
+			if err == nil && b.String() != "done" {
+				err = fmt.Errorf("output %q, want done", b.String())
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Execute() error = %v", err)
+		}
+	}
+}
+
+func TestHTMLIncludeFuncs(t *testing.T) {
+	funcs := FuncMap{"ToUpper": ToUpper}
+	tmpl := htmltmpl.New("test")
+	tmpl = htmltmpl.Must(tmpl.Funcs(HTMLIncludeFuncs(tmpl, funcs).HTML()).Funcs(funcs.HTML()).Parse(
+		`{{ define "b" }}<b>{{ . }}</b>{{ end }}{{ Include "b" "<i>" }}{{ Tpl "{{ ToUpper . }}" "<p>" }}`,
+	))
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "<b>&lt;i&gt;</b>&lt;P&gt;"
+	if got := b.String(); got != want {
+		t.Errorf("Execute() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,221 @@
+package temple
+
+import (
+	"fmt"
+	"math"
+)
+
+// IntSum adds the numbers without converting them to float64, so large
+// integers keep their precision. The arguments are the same as for Max, but
+// every number must be an integer. Floats without a fractional part, such as
+// those decoded from JSON, are accepted. An error is returned if the sum
+// overflows an int64.
+//		{{ IntSum 9007199254740993 1 }} -> 9007199254740994
+func IntSum(x interface{}, vals ...interface{}) (int64, error) {
+	return foldInts("IntSum", addInt64, x, vals)
+}
+
+// IntDiff subtracts each of the numbers from the first one. See IntSum for the
+// accepted arguments.
+func IntDiff(x interface{}, vals ...interface{}) (int64, error) {
+	return foldInts("IntDiff", func(a, b int64) (int64, bool) {
+		if b == math.MinInt64 {
+			// -b overflows, but a - b does not if a is negative.
+			if a >= 0 {
+				return 0, false
+			}
+			return a - b, true
+		}
+		return addInt64(a, -b)
+	}, x, vals)
+}
+
+// IntMul multiplies the numbers. See IntSum for the accepted arguments.
+func IntMul(x interface{}, vals ...interface{}) (int64, error) {
+	return foldInts("IntMul", mulInt64, x, vals)
+}
+
+// IntDiv returns the quotient of x/y truncated toward zero, and IntMod returns
+// the matching remainder, which has the sign of x. An error is returned if y
+// is zero.
+//		{{ IntDiv 7 2 }} -> 3
+//		{{ IntMod -7 2 }} -> -1
+func IntDiv(x, y interface{}) (int64, error) {
+	a, b, err := divArgs(x, y)
+	if err == nil && a == math.MinInt64 && b == -1 {
+		err = ErrOverflow
+	}
+	if err != nil {
+		return 0, funcError("IntDiv", err, x, y)
+	}
+	return a / b, nil
+}
+
+// IntMod returns the remainder of x/y. See IntDiv. Unlike the quotient, the
+// remainder of math.MinInt64 / -1 fits in an int64.
+func IntMod(x, y interface{}) (int64, error) {
+	a, b, err := divArgs(x, y)
+	if err != nil {
+		return 0, funcError("IntMod", err, x, y)
+	}
+	return a % b, nil
+}
+
+// divArgs converts the arguments of IntDiv and IntMod.
+func divArgs(x, y interface{}) (int64, int64, error) {
+	a, err := toInt64(x)
+	if err != nil {
+		return 0, 0, err
+	}
+	b, err := toInt64(y)
+	if err != nil {
+		return 0, 0, err
+	}
+	if b == 0 {
+		return 0, 0, ErrDivisionByZero
+	}
+	return a, b, nil
+}
+
+// IntPow returns x raised to the power n, which must not be negative.
+//		{{ IntPow 2 62 }} -> 4611686018427387904
+func IntPow(x, n interface{}) (int64, error) {
+	p, err := powInt64(x, n)
+	return p, funcError("IntPow", err, x, n)
+}
+
+func powInt64(x, n interface{}) (int64, error) {
+	base, err := toInt64(x)
+	if err != nil {
+		return 0, err
+	}
+	exp, err := toInt64(n)
+	if err != nil {
+		return 0, err
+	}
+	if exp < 0 {
+		return 0, fmt.Errorf("negative exponent %d", exp)
+	}
+
+	// Exponentiation by squaring. The base is only squared while bits of the
+	// exponent remain, so squaring it cannot overflow needlessly.
+	result, ok := int64(1), true
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			if result, ok = mulInt64(result, base); !ok {
+				return 0, ErrOverflow
+			}
+		}
+		if exp > 1 {
+			if base, ok = mulInt64(base, base); !ok {
+				return 0, ErrOverflow
+			}
+		}
+	}
+	return result, nil
+}
+
+// IntAbs returns the absolute value of x. An error is returned for the
+// smallest int64, whose absolute value does not fit.
+func IntAbs(x interface{}) (int64, error) {
+	i, err := toInt64(x)
+	if err == nil && i == math.MinInt64 {
+		err = ErrOverflow
+	}
+	if err != nil {
+		return 0, funcError("IntAbs", err, x)
+	}
+	if i < 0 {
+		return -i, nil
+	}
+	return i, nil
+}
+
+func foldInts(name string, op func(a, b int64) (int64, bool), x interface{}, vals []interface{}) (int64, error) {
+	args := append([]interface{}{x}, vals...)
+	nums, err := parseInt64Args(args)
+	if err == nil && len(nums) == 0 {
+		err = ErrEmpty
+	}
+	if err != nil {
+		return 0, funcError(name, err, args...)
+	}
+
+	r := nums[0]
+	for _, v := range nums[1:] {
+		var ok bool
+		if r, ok = op(r, v); !ok {
+			return 0, funcError(name, ErrOverflow, args...)
+		}
+	}
+	return r, nil
+}
+
+// parseInt64Args flattens the arguments into int64s. Unlike parseIntArgs,
+// floats are not truncated. See toInt64.
+func parseInt64Args(args []interface{}) ([]int64, error) {
+	nums, _, err := parseNumbers(args)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]int64, len(nums))
+	for i, n := range nums {
+		if vals[i], err = n.int64(); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// toInt64 converts a single integer argument. See toNumber for the accepted
+// types.
+func toInt64(v interface{}) (int64, error) {
+	n, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.int64()
+}
+
+// int64 returns the number as an int64. Floats and big numbers must be whole
+// numbers within the range of an int64.
+func (n number) int64() (int64, error) {
+	if n.r != nil {
+		if !n.r.IsInt() {
+			return 0, fmt.Errorf("%v is not an integer", n.r.RatString())
+		}
+		if !n.r.Num().IsInt64() {
+			return 0, ErrOverflow
+		}
+		return n.r.Num().Int64(), nil
+	}
+	if !n.float {
+		return n.i, nil
+	}
+	if n.f != math.Trunc(n.f) {
+		return 0, fmt.Errorf("%v is not an integer", n.f)
+	}
+	// -2^63 is exactly representable as a float64, but 2^63-1 is not, so the
+	// upper bound is exclusive.
+	if n.f < math.MinInt64 || n.f >= math.MaxInt64 {
+		return 0, ErrOverflow
+	}
+	return int64(n.f), nil
+}
+
+func addInt64(a, b int64) (int64, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) || c/b != a {
+		return 0, false
+	}
+	return c, true
+}
@@ -0,0 +1,64 @@
+package temple
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIntArithmetic(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() (int64, error)
+		want    int64
+		wantErr error
+	}{
+		{name: "IntSum", fn: func() (int64, error) { return IntSum(1, []int{2, 3}) }, want: 6},
+		{name: "IntSum precision", fn: func() (int64, error) { return IntSum(int64(9007199254740993), 1) }, want: 9007199254740994},
+		{name: "IntSum json.Number", fn: func() (int64, error) { return IntSum(json.Number("9007199254740993"), "1") }, want: 9007199254740994},
+		{name: "IntSum whole float", fn: func() (int64, error) { return IntSum(2.0, 3) }, want: 5},
+		{name: "IntSum fraction", fn: func() (int64, error) { return IntSum(2.5, 3) }, wantErr: errors.New("")},
+		{name: "IntSum overflow", fn: func() (int64, error) { return IntSum(int64(math.MaxInt64), 1) }, wantErr: ErrOverflow},
+		{name: "IntSum negative overflow", fn: func() (int64, error) { return IntSum(int64(math.MinInt64), -1) }, wantErr: ErrOverflow},
+		{name: "IntSum large float", fn: func() (int64, error) { return IntSum(1e19) }, wantErr: ErrOverflow},
+		{name: "IntSum empty", fn: func() (int64, error) { return IntSum([]int{}) }, wantErr: ErrEmpty},
+		{name: "IntDiff", fn: func() (int64, error) { return IntDiff(10, 3, 2) }, want: 5},
+		{name: "IntDiff min", fn: func() (int64, error) { return IntDiff(-1, int64(math.MinInt64)) }, want: math.MaxInt64},
+		{name: "IntDiff overflow", fn: func() (int64, error) { return IntDiff(0, int64(math.MinInt64)) }, wantErr: ErrOverflow},
+		{name: "IntMul", fn: func() (int64, error) { return IntMul(3, 4, -5) }, want: -60},
+		{name: "IntMul overflow", fn: func() (int64, error) { return IntMul(int64(1)<<32, int64(1)<<31) }, wantErr: ErrOverflow},
+		{name: "IntMul min", fn: func() (int64, error) { return IntMul(int64(math.MinInt64), -1) }, wantErr: ErrOverflow},
+		{name: "IntDiv", fn: func() (int64, error) { return IntDiv(-7, 2) }, want: -3},
+		{name: "IntDiv by zero", fn: func() (int64, error) { return IntDiv(7, 0) }, wantErr: ErrDivisionByZero},
+		{name: "IntDiv overflow", fn: func() (int64, error) { return IntDiv(int64(math.MinInt64), -1) }, wantErr: ErrOverflow},
+		{name: "IntMod", fn: func() (int64, error) { return IntMod(-7, 2) }, want: -1},
+		{name: "IntMod by zero", fn: func() (int64, error) { return IntMod(7, 0) }, wantErr: ErrDivisionByZero},
+		{name: "IntMod MinInt64", fn: func() (int64, error) { return IntMod(int64(math.MinInt64), -1) }, want: 0},
+		{name: "IntPow", fn: func() (int64, error) { return IntPow(2, 62) }, want: 1 << 62},
+		{name: "IntPow negative base", fn: func() (int64, error) { return IntPow(-3, 3) }, want: -27},
+		{name: "IntPow zero", fn: func() (int64, error) { return IntPow(0, 0) }, want: 1},
+		{name: "IntPow min", fn: func() (int64, error) { return IntPow(-2, 63) }, want: math.MinInt64},
+		{name: "IntPow overflow", fn: func() (int64, error) { return IntPow(2, 63) }, wantErr: ErrOverflow},
+		{name: "IntPow negative exponent", fn: func() (int64, error) { return IntPow(2, -1) }, wantErr: errors.New("")},
+		{name: "IntAbs", fn: func() (int64, error) { return IntAbs("-5") }, want: 5},
+		{name: "IntAbs overflow", fn: func() (int64, error) { return IntAbs(int64(math.MinInt64)) }, wantErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if tt.wantErr.Error() != "" && !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
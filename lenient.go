@@ -0,0 +1,72 @@
+package temple
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Lenient wraps a function returning a value and an error so that errors are
+// replaced by def. The returned function has the same arguments as fn, but
+// only returns the value. If def is nil, the zero value of the result type is
+// used instead. Lenient panics if fn does not return a value and an error, or
+// if def cannot be converted to the result type.
+//
+// For example, a lenient Div results in 0 instead of an error when dividing by
+// zero:
+//		funcs := temple.FuncMap{"Div": temple.Lenient(temple.Div, nil)}
+func Lenient(fn interface{}, def interface{}) interface{} {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumOut() != 2 || t.Out(1) != errorType {
+		panic(fmt.Sprintf("temple: Lenient requires a function returning a value and an error, got %v", t))
+	}
+
+	out := t.Out(0)
+	d := reflect.Zero(out)
+	if def != nil {
+		dv := reflect.ValueOf(def)
+		if !dv.Type().ConvertibleTo(out) {
+			panic(fmt.Sprintf("temple: Lenient default %v cannot be converted to %v", def, out))
+		}
+		d = dv.Convert(out)
+	}
+
+	in := make([]reflect.Type, t.NumIn())
+	for i := range in {
+		in[i] = t.In(i)
+	}
+	ft := reflect.FuncOf(in, []reflect.Type{out}, t.IsVariadic())
+
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		var res []reflect.Value
+		if t.IsVariadic() {
+			res = v.CallSlice(args)
+		} else {
+			res = v.Call(args)
+		}
+
+		if !res[1].IsNil() {
+			return []reflect.Value{d}
+		}
+		return res[:1]
+	}).Interface()
+}
+
+// LenientFuncMap returns a copy of f in which every function returning a value
+// and an error is wrapped with Lenient. The defaults map function names to the
+// values returned in place of errors. Functions without a default return the
+// zero value of their result type.
+func LenientFuncMap(f FuncMap, defaults map[string]interface{}) FuncMap {
+	out := make(FuncMap, len(f))
+	for name, fn := range f {
+		t := reflect.TypeOf(fn)
+		if t == nil || t.Kind() != reflect.Func || t.NumOut() != 2 || t.Out(1) != errorType {
+			out[name] = fn
+			continue
+		}
+		out[name] = Lenient(fn, defaults[name])
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package temple
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	texttmpl "text/template"
+)
+
+func TestLenientFuncMap(t *testing.T) {
+	funcs := LenientFuncMap(FullFuncMap(), map[string]interface{}{"Commas": "N/A"})
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{name: "Div by zero", tmpl: `{{ Div 1 0 }}`, want: "0"},
+		{name: "Div", tmpl: `{{ Div 6 2 }}`, want: "3"},
+		{name: "Mod by zero", tmpl: `{{ Mod 1.0 0.0 }}`, want: "0"},
+		{name: "Default", tmpl: `{{ Commas "abc" }}`, want: "N/A"},
+		{name: "No error", tmpl: `{{ Commas "1234" }}`, want: "1,234"},
+		{name: "Without error result", tmpl: `{{ IsNumeric "12" }}`, want: "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := texttmpl.Must(texttmpl.New("").Funcs(funcs.Text()).Parse(tt.tmpl))
+
+			var b strings.Builder
+			if err := tmpl.Execute(&b, nil); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuncError(t *testing.T) {
+	_, err := Div(1, 2, 0)
+
+	var fe *FuncError
+	if !errors.As(err, &fe) || fe.Func != "Div" {
+		t.Fatalf("Div() error = %v, want *FuncError for Div", err)
+	}
+	if !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Div() error = %v, want ErrDivisionByZero", err)
+	}
+	if got, want := err.Error(), "Div(1, 2, 0): division by zero"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	_, err = IntMax(NewList(1, "a"))
+	if !errors.As(err, &fe) || fe.Func != "IntMax" {
+		t.Errorf("IntMax() error = %v, want *FuncError for IntMax", err)
+	}
+
+	_, err = Commas("x" + strings.Repeat("é", 20))
+	if got, want := err.Error(), `Commas("x`+strings.Repeat("é", 13)+`...): non numeric string`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,488 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+	htmltmpl "html/template"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	texttmpl "text/template"
+	"text/template/parse"
+	"time"
+)
+
+// Limits bounds the resources used while executing a template. A zero value
+// for any of the fields disables that limit.
+type Limits struct {
+	// MaxOutputBytes is the maximum number of bytes written to the output.
+	// The output of each Include and Tpl call is limited to the same number
+	// of bytes.
+	MaxOutputBytes int64
+	// Timeout is the maximum duration of an execution.
+	Timeout time.Duration
+	// MaxRangeIterations is the maximum total number of range iterations
+	// performed during an execution, across all range actions.
+	MaxRangeIterations int
+	// MaxDepth is the maximum number of nested template invocations, such as
+	// through the template action or Include. The executed template itself
+	// counts as the first level.
+	MaxDepth int
+}
+
+// OutputLimitError is returned when a template writes more than
+// Limits.MaxOutputBytes.
+type OutputLimitError struct {
+	Limit int64
+}
+
+func (e *OutputLimitError) Error() string {
+	return fmt.Sprintf("temple: output exceeds %d bytes", e.Limit)
+}
+
+// TimeoutError is returned when an execution exceeds Limits.Timeout or its
+// context is canceled. Err is the error of the context.
+type TimeoutError struct {
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("temple: execution stopped: %v", e.Err)
+}
+
+// Unwrap returns the error of the context.
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// RangeLimitError is returned when an execution performs more than
+// Limits.MaxRangeIterations range iterations.
+type RangeLimitError struct {
+	Limit int
+}
+
+func (e *RangeLimitError) Error() string {
+	return fmt.Sprintf("temple: more than %d range iterations", e.Limit)
+}
+
+// DepthLimitError is returned when templates are nested more than
+// Limits.MaxDepth levels deep.
+type DepthLimitError struct {
+	Limit int
+}
+
+func (e *DepthLimitError) Error() string {
+	return fmt.Sprintf("temple: templates nested more than %d levels deep", e.Limit)
+}
+
+// The names of the functions called by the actions added to limited
+// templates.
+const (
+	limitTickFunc  = "_temple_limit_tick"
+	limitEnterFunc = "_temple_limit_enter"
+	limitLeaveFunc = "_temple_limit_leave"
+)
+
+// ExecuteText executes t with the limits, writing the output to w. Execution
+// stops when ctx is done, with the context's error wrapped in a
+// *TimeoutError. Exceeding a limit results in an *OutputLimitError,
+// *TimeoutError, *RangeLimitError or *DepthLimitError.
+//
+// To enforce the limits, the templates of a copy of t are modified to call
+// additional functions, and the copy is executed instead of t. The Include
+// and Tpl functions of TextIncludeFuncs are replaced in the copy by functions
+// executing the copy, so that they are limited as well. They are found by
+// their names, so they fail if they were added under other names. Templates
+// of the same set may be executed concurrently.
+//
+// Functions that block are not interrupted. If ctx is done while a function
+// blocks, ExecuteText returns immediately and the execution stops once the
+// function returns. Nothing is written to w after ExecuteText returns.
+func (l Limits) ExecuteText(ctx context.Context, t *texttmpl.Template, w io.Writer, data interface{}) error {
+	c, err := t.Clone()
+	if err != nil {
+		return err
+	}
+
+	var trees []*parse.Tree
+	for _, tt := range c.Templates() {
+		if tt.Tree == nil {
+			continue
+		}
+		tree := tt.Tree.Copy()
+		nt, err := c.AddParseTree(tt.Name(), tree)
+		if err != nil {
+			return err
+		}
+		// An empty tree does not replace the tree of an existing template,
+		// in which case the tree is still shared with t.
+		if nt.Tree == tree {
+			trees = append(trees, tree)
+		}
+	}
+
+	return l.execute(ctx, trees, w, func(lim *limiter) error {
+		if err := bindText(c, lim); err != nil {
+			return err
+		}
+		return c.Funcs(lim.funcs().Text()).Execute(lim, data)
+	})
+}
+
+// ExecuteHTML is like ExecuteText but executes an html/template, with the
+// Include and Tpl functions bound by HTMLIncludeFuncs. Since t is copied, the
+// limits must be applied before t is executed for the first time.
+func (l Limits) ExecuteHTML(ctx context.Context, t *htmltmpl.Template, w io.Writer, data interface{}) error {
+	c, err := t.Clone()
+	if err != nil {
+		return err
+	}
+
+	var trees []*parse.Tree
+	for _, tt := range c.Templates() {
+		if tt.Tree == nil {
+			continue
+		}
+		tree := tt.Tree.Copy()
+		nt, err := c.AddParseTree(tt.Name(), tree)
+		if err != nil {
+			return err
+		}
+		if nt.Tree == tree {
+			trees = append(trees, tree)
+		}
+	}
+	// AddParseTree replaces the template of the copy named like t.
+	c = c.Lookup(t.Name())
+
+	return l.execute(ctx, trees, w, func(lim *limiter) error {
+		if err := bindHTML(c, lim); err != nil {
+			return err
+		}
+		return c.Funcs(lim.funcs().HTML()).Execute(lim, data)
+	})
+}
+
+func (l Limits) execute(ctx context.Context, trees []*parse.Tree, w io.Writer, exec func(*limiter) error) error {
+	if l.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Timeout)
+		defer cancel()
+	}
+
+	instrument(trees)
+
+	lim := &limiter{limits: l, ctx: ctx, w: w}
+	done := make(chan error, 1)
+	go func() {
+		done <- runLimited(func() error { return exec(lim) })
+	}()
+
+	select {
+	case err := <-done:
+		if lerr := lim.error(); lerr != nil {
+			return lerr
+		}
+		return err
+	case <-ctx.Done():
+		return lim.fail(&TimeoutError{Err: ctx.Err()})
+	}
+}
+
+// runLimited runs a limited execution. Include and Tpl functions that are
+// not bound to the execution find this call on the stack and fail. See nest.
+func runLimited(exec func() error) error {
+	return exec()
+}
+
+// probeName is the name of the templates parsed by probeText and probeHTML.
+const probeName = "temple.probe"
+
+// bindText replaces the functions of TextIncludeFuncs in c by functions
+// bound to c and lim.
+func bindText(c *texttmpl.Template, lim *limiter) error {
+	bound := textIncludeFuncs(c, lim)
+	for _, name := range sortedNames(bound) {
+		p, err := probeText(c, name)
+		if err != nil {
+			return err
+		}
+		if p.found {
+			c.Funcs(FuncMap{name: bound[name]}.Text())
+		}
+	}
+	return nil
+}
+
+// bindHTML is like bindText for the functions of HTMLIncludeFuncs.
+func bindHTML(c *htmltmpl.Template, lim *limiter) error {
+	var bound FuncMap
+	for _, name := range []string{"Include", "Tpl"} {
+		p, err := probeHTML(c, name)
+		if err != nil {
+			return err
+		}
+		if !p.found {
+			continue
+		}
+		if bound == nil {
+			bound = htmlIncludeFuncs(c, p.funcs, lim)
+		}
+		c.Funcs(FuncMap{name: bound[name]}.HTML())
+	}
+	return nil
+}
+
+// probeText calls the named function of a copy of c with an includeProbe. As
+// the probe is executed in a copy, c is left unchanged. A function that is not
+// defined or fails leaves the probe unmarked.
+func probeText(c *texttmpl.Template, name string) (*includeProbe, error) {
+	p, err := c.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	var probe includeProbe
+	if p, err = p.New(probeName).Parse("{{ " + name + ` "" . }}`); err == nil {
+		p.Execute(ioutil.Discard, &probe)
+	}
+	return &probe, nil
+}
+
+// probeHTML is like probeText for html/template.
+func probeHTML(c *htmltmpl.Template, name string) (*includeProbe, error) {
+	p, err := c.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	var probe includeProbe
+	if p, err = p.New(probeName).Parse("{{ " + name + ` "" . }}`); err == nil {
+		p.Execute(ioutil.Discard, &probe)
+	}
+	return &probe, nil
+}
+
+func textTrees(t *texttmpl.Template) []*parse.Tree {
+	var trees []*parse.Tree
+	for _, tt := range t.Templates() {
+		if tt.Tree != nil {
+			trees = append(trees, tt.Tree)
+		}
+	}
+	return trees
+}
+
+func htmlTrees(t *htmltmpl.Template) []*parse.Tree {
+	var trees []*parse.Tree
+	for _, tt := range t.Templates() {
+		if tt.Tree != nil {
+			trees = append(trees, tt.Tree)
+		}
+	}
+	return trees
+}
+
+// limiter tracks the resources used by an execution. It is also the writer
+// that the output is written through.
+type limiter struct {
+	limits Limits
+	ctx    context.Context
+	w      io.Writer
+
+	mu         sync.Mutex
+	err        error
+	written    int64
+	iterations int
+	depth      int
+}
+
+func (l *limiter) funcs() FuncMap {
+	return FuncMap{
+		limitTickFunc: func() (string, error) {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.iterations++
+			if max := l.limits.MaxRangeIterations; max > 0 && l.iterations > max {
+				return "", l.failLocked(&RangeLimitError{Limit: max})
+			}
+			return "", l.checkLocked()
+		},
+		limitEnterFunc: func() (string, error) {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.depth++
+			if max := l.limits.MaxDepth; max > 0 && l.depth > max {
+				return "", l.failLocked(&DepthLimitError{Limit: max})
+			}
+			return "", l.checkLocked()
+		},
+		limitLeaveFunc: func() string {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.depth--
+			return ""
+		},
+	}
+}
+
+// Write implements io.Writer. Once the output limit is reached, the bytes up
+// to the limit are written and an error is returned. Nothing is written after
+// the execution has stopped, including after a timeout while the execution
+// is still running. As Write holds the lock while writing, nothing is written
+// once fail has returned.
+func (l *limiter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.checkLocked(); err != nil {
+		return 0, err
+	}
+
+	if max := l.limits.MaxOutputBytes; max > 0 && l.written+int64(len(p)) > max {
+		n, err := l.w.Write(p[:max-l.written])
+		l.written += int64(n)
+		if err != nil {
+			return n, err
+		}
+		return n, l.failLocked(&OutputLimitError{Limit: max})
+	}
+
+	n, err := l.w.Write(p)
+	l.written += int64(n)
+	return n, err
+}
+
+// checkLocked returns the error that stopped the execution, if any.
+func (l *limiter) checkLocked() error {
+	if l.err != nil {
+		return l.err
+	}
+	if err := l.ctx.Err(); err != nil {
+		l.err = &TimeoutError{Err: err}
+	}
+	return l.err
+}
+
+// failLocked stops the execution with err unless it was already stopped.
+func (l *limiter) failLocked(err error) error {
+	if l.err == nil {
+		l.err = err
+	}
+	return l.err
+}
+
+// buffer returns a writer appending to b, such as for the output of Include.
+// Like the output, b cannot grow beyond MaxOutputBytes. If l is nil, b is
+// returned.
+func (l *limiter) buffer(b *strings.Builder) io.Writer {
+	if l == nil {
+		return b
+	}
+	return &limitedBuffer{l: l, b: b}
+}
+
+type limitedBuffer struct {
+	l *limiter
+	b *strings.Builder
+}
+
+func (w *limitedBuffer) Write(p []byte) (int, error) {
+	w.l.mu.Lock()
+	defer w.l.mu.Unlock()
+
+	if err := w.l.checkLocked(); err != nil {
+		return 0, err
+	}
+	if max := w.l.limits.MaxOutputBytes; max > 0 && int64(w.b.Len()+len(p)) > max {
+		return 0, w.l.failLocked(&OutputLimitError{Limit: max})
+	}
+	return w.b.Write(p)
+}
+
+func (l *limiter) fail(err error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.failLocked(err)
+}
+
+func (l *limiter) error() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// instrumentMu guards the instrumentation of trees, which may be shared by
+// the templates cloned by Tpl.
+var instrumentMu sync.Mutex
+
+// instrument adds the actions enforcing the limits to the trees. Each template
+// enters and leaves a level of depth, and each range iteration ticks. Trees
+// that are already instrumented are skipped.
+func instrument(trees []*parse.Tree) {
+	instrumentMu.Lock()
+	defer instrumentMu.Unlock()
+
+	for _, tree := range trees {
+		if tree == nil || tree.Root == nil || isLimitAction(firstNode(tree.Root), limitEnterFunc) {
+			continue
+		}
+
+		instrumentRanges(tree.Root)
+		tree.Root.Nodes = append([]parse.Node{limitAction(limitEnterFunc)}, tree.Root.Nodes...)
+		tree.Root.Nodes = append(tree.Root.Nodes, limitAction(limitLeaveFunc))
+	}
+}
+
+func instrumentRanges(list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+
+	for _, n := range list.Nodes {
+		switch n := n.(type) {
+		case *parse.IfNode:
+			instrumentRanges(n.List)
+			instrumentRanges(n.ElseList)
+		case *parse.WithNode:
+			instrumentRanges(n.List)
+			instrumentRanges(n.ElseList)
+		case *parse.RangeNode:
+			instrumentRanges(n.List)
+			instrumentRanges(n.ElseList)
+			if n.List != nil {
+				n.List.Nodes = append([]parse.Node{limitAction(limitTickFunc)}, n.List.Nodes...)
+			}
+		}
+	}
+}
+
+// limitAction creates an action calling the named function. Since parse nodes
+// cannot be created outside of the parse package, the action is parsed from a
+// separate snippet and keeps the positions within that snippet.
+func limitAction(name string) parse.Node {
+	funcs := map[string]interface{}{name: func() string { return "" }}
+	t, err := parse.New(name).Parse("{{"+name+"}}", "{{", "}}", make(map[string]*parse.Tree), funcs)
+	if err != nil {
+		panic(err)
+	}
+	return t.Root.Nodes[0]
+}
+
+func isLimitAction(n parse.Node, name string) bool {
+	a, ok := n.(*parse.ActionNode)
+	if !ok || a.Pipe == nil || len(a.Pipe.Cmds) != 1 || len(a.Pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	id, ok := a.Pipe.Cmds[0].Args[0].(*parse.IdentifierNode)
+	return ok && id.Ident == name
+}
+
+func firstNode(list *parse.ListNode) parse.Node {
+	if len(list.Nodes) == 0 {
+		return nil
+	}
+	return list.Nodes[0]
+}
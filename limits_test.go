@@ -0,0 +1,330 @@
+package temple
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltmpl "html/template"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	texttmpl "text/template"
+	"time"
+)
+
+func TestLimits_ExecuteText(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		tmpl    string
+		data    interface{}
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "Within limits",
+			limits: Limits{MaxOutputBytes: 100, MaxRangeIterations: 3, MaxDepth: 2, Timeout: time.Second},
+			tmpl:   `{{ define "item" }}<{{ . }}>{{ end }}{{ range . }}{{ template "item" . }}{{ end }}`,
+			data:   []int{1, 2, 3},
+			want:   "<1><2><3>",
+		},
+		{
+			name:    "Output",
+			limits:  Limits{MaxOutputBytes: 5},
+			tmpl:    `{{ range . }}{{ . }}{{ end }}`,
+			data:    []string{"abc", "def"},
+			want:    "abcde",
+			wantErr: &OutputLimitError{},
+		},
+		{
+			name:    "Range iterations",
+			limits:  Limits{MaxRangeIterations: 3},
+			tmpl:    `{{ range . }}{{ range . }}{{ . }}{{ end }}{{ end }}`,
+			data:    [][]int{{1}, {2}},
+			want:    "1",
+			wantErr: &RangeLimitError{},
+		},
+		{
+			name:    "Depth",
+			limits:  Limits{MaxDepth: 10},
+			tmpl:    `{{ define "loop" }}{{ template "loop" . }}{{ end }}{{ template "loop" . }}`,
+			wantErr: &DepthLimitError{},
+		},
+		{
+			name:    "Timeout",
+			limits:  Limits{Timeout: 10 * time.Millisecond},
+			tmpl:    `{{ define "fork" }}{{ template "fork" . }}{{ template "fork" . }}{{ end }}{{ template "fork" . }}`,
+			wantErr: &TimeoutError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := texttmpl.Must(texttmpl.New("").Parse(tt.tmpl))
+
+			var b strings.Builder
+			err := tt.limits.ExecuteText(context.Background(), tmpl, &b, tt.data)
+			if !sameErrorType(err, tt.wantErr) {
+				t.Fatalf("ExecuteText() error = %v, want %T", err, tt.wantErr)
+			}
+			if tt.want != "" && b.String() != tt.want {
+				t.Errorf("ExecuteText() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLimits_ExecuteHTML(t *testing.T) {
+	tmpl := htmltmpl.Must(htmltmpl.New("").Parse(`{{ range . }}<a href="{{ . }}">{{ . }}</a>{{ end }}`))
+
+	var b strings.Builder
+	err := Limits{MaxRangeIterations: 2}.ExecuteHTML(context.Background(), tmpl, &b, []string{"a b", "<c>"})
+	if err != nil {
+		t.Fatalf("ExecuteHTML() error = %v", err)
+	}
+	if got, want := b.String(), `<a href="a%20b">a b</a><a href="%3cc%3e">&lt;c&gt;</a>`; got != want {
+		t.Errorf("ExecuteHTML() = %q, want %q", got, want)
+	}
+
+	err = Limits{MaxRangeIterations: 1}.ExecuteHTML(context.Background(), tmpl, &b, []string{"a", "b"})
+	var rerr *RangeLimitError
+	if !errors.As(err, &rerr) {
+		t.Errorf("ExecuteHTML() error = %v, want *RangeLimitError", err)
+	}
+}
+
+func TestLimits_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tmpl := texttmpl.Must(texttmpl.New("").Parse(`{{ range . }}{{ . }}{{ end }}`))
+	err := Limits{}.ExecuteText(ctx, tmpl, &strings.Builder{}, []int{1, 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ExecuteText() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestLimits_include(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		tmpl    string
+		wantErr error
+	}{
+		{
+			name:   "Within limits",
+			limits: Limits{MaxOutputBytes: 10, MaxRangeIterations: 6, MaxDepth: 3},
+			tmpl:   `{{ define "row" }}{{ range . }}{{ . }}{{ end }}{{ end }}{{ Include "row" . }}{{ Tpl "{{ range . }}{{ . }}{{ end }}" . }}`,
+		},
+		{
+			name:    "Include output",
+			limits:  Limits{MaxOutputBytes: 2},
+			tmpl:    `{{ define "row" }}{{ range . }}{{ . }}{{ end }}{{ end }}{{ Include "row" . | len }}`,
+			wantErr: &OutputLimitError{},
+		},
+		{
+			name:    "Tpl output",
+			limits:  Limits{MaxOutputBytes: 2},
+			tmpl:    `{{ Tpl "{{ range . }}{{ . }}{{ end }}" . | len }}`,
+			wantErr: &OutputLimitError{},
+		},
+		{
+			name:    "Include depth",
+			limits:  Limits{MaxDepth: 10},
+			tmpl:    `{{ define "loop" }}{{ Include "loop" . }}{{ end }}{{ Include "loop" . }}`,
+			wantErr: &DepthLimitError{},
+		},
+		{
+			name:    "Tpl range iterations",
+			limits:  Limits{MaxRangeIterations: 2},
+			tmpl:    `{{ Tpl "{{ range . }}{{ . }}{{ end }}" . }}`,
+			wantErr: &RangeLimitError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := texttmpl.New("")
+			tmpl = texttmpl.Must(tmpl.Funcs(TextIncludeFuncs(tmpl).Text()).Parse(tt.tmpl))
+
+			err := tt.limits.ExecuteText(context.Background(), tmpl, &strings.Builder{}, []int{1, 2, 3})
+			if !sameErrorType(err, tt.wantErr) {
+				t.Fatalf("ExecuteText() error = %v, want %T", err, tt.wantErr)
+			}
+		})
+	}
+
+	tmpl := htmltmpl.New("")
+	tmpl = htmltmpl.Must(tmpl.Funcs(HTMLIncludeFuncs(tmpl, nil).HTML()).Parse(
+		`{{ define "row" }}{{ range . }}{{ . }}{{ end }}{{ end }}{{ Include "row" . }}`,
+	))
+	err := Limits{MaxOutputBytes: 2}.ExecuteHTML(context.Background(), tmpl, &strings.Builder{}, []int{1, 2, 3})
+	if !sameErrorType(err, &OutputLimitError{}) {
+		t.Errorf("ExecuteHTML() error = %v, want *OutputLimitError", err)
+	}
+}
+
+func TestLimits_includeConcurrent(t *testing.T) {
+	// Both executions wait for each other inside Include, so that each
+	// Include call runs while the other execution is in progress.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	both := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(both)
+	}()
+
+	funcs := FuncMap{
+		"Wait": func() (string, error) {
+			wg.Done()
+			select {
+			case <-both:
+				return "", nil
+			case <-time.After(5 * time.Second):
+				return "", errors.New("the other execution did not call Include")
+			}
+		},
+	}
+	tmpl := texttmpl.New("")
+	tmpl = texttmpl.Must(tmpl.Funcs(TextIncludeFuncs(tmpl).Text()).Funcs(funcs.Text()).Parse(
+		`{{ define "row" }}{{ Wait }}0123456789{{ end }}{{ Include "row" . | len }}`,
+	))
+
+	tests := []struct {
+		limits  Limits
+		want    string
+		wantErr error
+	}{
+		{limits: Limits{MaxOutputBytes: 5}, wantErr: &OutputLimitError{}},
+		{limits: Limits{MaxOutputBytes: 100}, want: "10"},
+	}
+	errs := make(chan error, len(tests))
+	for _, tt := range tests {
+		go func(limits Limits, want string, wantErr error) {
+			var b strings.Builder
+			err := limits.ExecuteText(context.Background(), tmpl, &b, nil)
+			switch {
+			case !sameErrorType(err, wantErr):
+				errs <- fmt.Errorf("ExecuteText() with %d bytes error = %v, want %T", limits.MaxOutputBytes, err, wantErr)
+			case b.String() != want:
+				errs <- fmt.Errorf("ExecuteText() with %d bytes = %q, want %q", limits.MaxOutputBytes, b.String(), want)
+			default:
+				errs <- nil
+			}
+		}(tt.limits, tt.want, tt.wantErr)
+	}
+	for range tests {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestLimits_includeRenamed(t *testing.T) {
+	tmpl := texttmpl.New("")
+	tmpl = texttmpl.Must(tmpl.Funcs(PrefixFuncMap("x_", TextIncludeFuncs(tmpl)).Text()).Parse(
+		`{{ define "loop" }}{{ range . }}{{ end }}{{ end }}{{ x_Include "loop" . }}`,
+	))
+
+	err := Limits{MaxRangeIterations: 1}.ExecuteText(context.Background(), tmpl, &strings.Builder{}, []int{1, 2})
+	if err == nil || !strings.Contains(err.Error(), "not bound to the limited execution") {
+		t.Errorf("ExecuteText() error = %v, want an unbound Include", err)
+	}
+}
+
+func TestLimits_unmodified(t *testing.T) {
+	tmpl := texttmpl.Must(texttmpl.New("").Parse(`{{ define "item" }}<{{ . }}>{{ end }}{{ range . }}{{ template "item" . }}{{ end }}`))
+	if err := (Limits{MaxDepth: 2}).ExecuteText(context.Background(), tmpl, &strings.Builder{}, []int{1}); err != nil {
+		t.Fatalf("ExecuteText() error = %v", err)
+	}
+
+	for _, tt := range tmpl.Templates() {
+		if s := tt.Tree.Root.String(); strings.Contains(s, "_temple_limit") {
+			t.Errorf("ExecuteText() modified template %q: %s", tt.Name(), s)
+		}
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, []int{1, 2}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := b.String(), "<1><2>"; got != want {
+		t.Errorf("Execute() = %q, want %q", got, want)
+	}
+}
+
+// syncWriter is a writer that can be read while a timed out execution is
+// still running.
+type syncWriter struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func (w *syncWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.String()
+}
+
+func TestLimits_writeAfterTimeout(t *testing.T) {
+	release := make(chan struct{})
+	tmpl := texttmpl.Must(texttmpl.New("").Funcs(texttmpl.FuncMap{
+		"wait": func() string { <-release; return "" },
+	}).Parse(`before{{ wait }}after`))
+
+	var w syncWriter
+	err := Limits{Timeout: 10 * time.Millisecond}.ExecuteText(context.Background(), tmpl, &w, nil)
+	if !sameErrorType(err, &TimeoutError{}) {
+		t.Fatalf("ExecuteText() error = %v, want *TimeoutError", err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if !limitedRunning() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("execution still running")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got, want := w.String(), "before"; got != want {
+		t.Errorf("ExecuteText() wrote %q, want %q", got, want)
+	}
+}
+
+// limitedRunning reports whether the stack of any goroutine is running a
+// limited execution.
+func limitedRunning() bool {
+	buf := make([]byte, 1<<20)
+	return bytes.Contains(buf[:runtime.Stack(buf, true)], []byte("temple.runLimited("))
+}
+
+func sameErrorType(err, want error) bool {
+	switch want.(type) {
+	case nil:
+		return err == nil
+	case *OutputLimitError:
+		var e *OutputLimitError
+		return errors.As(err, &e)
+	case *RangeLimitError:
+		var e *RangeLimitError
+		return errors.As(err, &e)
+	case *DepthLimitError:
+		var e *DepthLimitError
+		return errors.As(err, &e)
+	case *TimeoutError:
+		var e *TimeoutError
+		return errors.As(err, &e)
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package temple
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Locale contains the symbols and patterns used to format numbers for a
+// locale.
+type Locale struct {
+	// Tag is the BCP 47 tag of the locale, such as "de-DE".
+	Tag string
+	// Decimal is the decimal mark, Group is the grouping separator, and
+	// Minus is the minus sign.
+	Decimal string
+	Group   string
+	Minus   string
+	// Grouping is the number of digits in the group nearest the decimal
+	// mark, and SecondaryGrouping is the number of digits in the remaining
+	// groups. For example, the Indian grouping of 1,00,00,000 is 3 and 2.
+	Grouping          int
+	SecondaryGrouping int
+	// MinGroupingDigits is the minimum number of digits before the first
+	// grouping separator. With 2, 1234 is not grouped but 12345 is.
+	MinGroupingDigits int
+	// CurrencyPattern and PercentPattern describe the placement of the
+	// number (#), the currency symbol (¤) and the minus sign (-). A pattern
+	// for negative numbers may follow a semicolon. Otherwise, negative
+	// numbers are prefixed with the minus sign.
+	CurrencyPattern string
+	PercentPattern  string
+	// Symbols maps currency codes to the symbols used by the locale. Other
+	// currencies use their common symbol or their code.
+	Symbols map[string]string
+}
+
+// LookupLocale returns the locale with the provided tag. Tags are matched case
+// insensitively, and underscores may be used instead of hyphens. If the region
+// of the tag is unknown or missing, the default region of the language is
+// used, so "de" and "de-LU" both result in "de-DE".
+func LookupLocale(tag string) (Locale, error) {
+	parts := strings.SplitN(strings.Replace(tag, "_", "-", -1), "-", 2)
+	lang := strings.ToLower(parts[0])
+
+	key := lang
+	if len(parts) == 2 {
+		key += "-" + strings.ToUpper(parts[1])
+	}
+	if _, ok := locales[key]; !ok {
+		key = defaultRegions[lang]
+	}
+
+	l, ok := locales[key]
+	if !ok {
+		return Locale{}, fmt.Errorf("%w %q", ErrUnknownLocale, tag)
+	}
+
+	loc := *l
+	loc.Tag = key
+	return loc, nil
+}
+
+// Locales returns the sorted tags of the supported locales.
+func Locales() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// FormatNumber formats a number with the decimal mark and grouping of a
+// locale. The number is the last argument so that it can be piped, and it may
+// be preceded by a number of decimal places. Without one, at most three
+// decimal places are shown. Numbers are rounded half-even.
+//		{{ FormatNumber "de-DE" 1234.5 }} -> 1.234,5
+//		{{ .Total | FormatNumber "hi-IN" 2 }} -> 12,34,567.00
+func FormatNumber(locale string, args ...interface{}) (string, error) {
+	loc, places, trim, d, err := formatArgs(locale, args, 3)
+	var s string
+	if err == nil {
+		s, err = loc.format(d, places, trim, "#", "")
+	}
+	return s, funcError("FormatNumber", err, append([]interface{}{locale}, args...)...)
+}
+
+// FormatPercent formats a ratio as a percentage for a locale, so 0.25 results
+// in 25%. The arguments are the same as for FormatNumber, but without a number
+// of decimal places, none are shown.
+//		{{ FormatPercent "fr-CH" 0.256 }} -> 26%
+//		{{ FormatPercent "de-DE" 1 0.256 }} -> 25,6 %
+func FormatPercent(locale string, args ...interface{}) (string, error) {
+	loc, places, _, d, err := formatArgs(locale, args, 0)
+	var s string
+	if err == nil {
+		d = Decimal{unscaled: d.int(), scale: d.scale - 2}.normalize()
+		s, err = loc.format(d, places, false, loc.PercentPattern, "")
+	}
+	return s, funcError("FormatPercent", err, append([]interface{}{locale}, args...)...)
+}
+
+// FormatCurrency formats an amount of a currency, identified by its ISO 4217
+// code, for a locale. The amount is rounded half-even to the minor unit of the
+// currency, such as cents, and the currency symbol is placed according to the
+// locale.
+//		{{ .Total | FormatCurrency "en-US" "USD" }} -> $1,234.50
+//		{{ .Total | FormatCurrency "de-DE" "EUR" }} -> 1.234,50 €
+func FormatCurrency(locale, code string, v interface{}) (string, error) {
+	s, err := formatCurrency(locale, code, v)
+	return s, funcError("FormatCurrency", err, locale, code, v)
+}
+
+func formatCurrency(locale, code string, v interface{}) (string, error) {
+	loc, err := LookupLocale(locale)
+	if err != nil {
+		return "", err
+	}
+
+	code = strings.ToUpper(code)
+	if len(code) != 3 || strings.IndexFunc(code, func(r rune) bool { return r < 'A' || r > 'Z' }) >= 0 {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+
+	cur, ok := currencies[code]
+	if !ok {
+		cur = currency{symbol: code, digits: 2}
+	}
+	symbol := cur.symbol
+	if s, ok := loc.Symbols[code]; ok {
+		symbol = s
+	}
+
+	d, err := toDecimal(v)
+	if err != nil {
+		return "", err
+	}
+	return loc.format(d, cur.digits, false, loc.CurrencyPattern, symbol)
+}
+
+// formatArgs parses the arguments of FormatNumber and FormatPercent. If no
+// number of decimal places is provided, def is returned along with true to
+// indicate that trailing zeros should be trimmed.
+func formatArgs(locale string, args []interface{}, def int) (Locale, int, bool, Decimal, error) {
+	loc, err := LookupLocale(locale)
+	if err != nil {
+		return Locale{}, 0, false, Decimal{}, err
+	}
+
+	places, trim := def, true
+	switch len(args) {
+	case 1:
+	case 2:
+		p, err := toNumber(args[0])
+		if err != nil || p.float {
+			return Locale{}, 0, false, Decimal{}, errors.New("number of decimal places must be an integer")
+		}
+		places, trim = int(p.i), false
+	default:
+		return Locale{}, 0, false, Decimal{}, fmt.Errorf("wrong number of arguments: want 1 or 2, got %d", len(args))
+	}
+
+	d, err := toDecimal(args[len(args)-1])
+	return loc, places, trim, d, err
+}
+
+// format rounds d to the provided number of decimal places, optionally trimming
+// trailing zeros, and formats it with the pattern.
+func (l Locale) format(d Decimal, places int, trim bool, pattern, symbol string) (string, error) {
+	d, err := d.Round(places, RoundHalfEven)
+	if err != nil {
+		return "", err
+	}
+
+	s := d.String()
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+	if trim {
+		frac = strings.TrimRight(frac, "0")
+	}
+
+	num := l.group(intPart)
+	if frac != "" {
+		num += l.Decimal + frac
+	}
+
+	pos, negPattern := pattern, "-"+pattern
+	if i := strings.IndexByte(pattern, ';'); i >= 0 {
+		pos, negPattern = pattern[:i], pattern[i+1:]
+	}
+	if neg {
+		pos = negPattern
+	}
+	return strings.NewReplacer("#", num, "¤", symbol, "-", l.Minus).Replace(pos), nil
+}
+
+// group inserts the grouping separators into a string of digits.
+func (l Locale) group(digits string) string {
+	if l.Grouping <= 0 || len(digits) < l.Grouping+l.MinGroupingDigits {
+		return digits
+	}
+
+	secondary := l.SecondaryGrouping
+	if secondary <= 0 {
+		secondary = l.Grouping
+	}
+
+	groups := []string{digits[len(digits)-l.Grouping:]}
+	rest := digits[:len(digits)-l.Grouping]
+	for len(rest) > secondary {
+		groups = append(groups, rest[len(rest)-secondary:])
+		rest = rest[:len(rest)-secondary]
+	}
+	if rest != "" {
+		groups = append(groups, rest)
+	}
+
+	for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+		groups[i], groups[j] = groups[j], groups[i]
+	}
+	return strings.Join(groups, l.Group)
+}
@@ -0,0 +1,185 @@
+package temple
+
+// The locale and currency data below is derived from the Unicode CLDR. Only
+// the symbols and patterns used by the formatting functions are included.
+
+const (
+	nbsp       = "\u00a0" // no-break space
+	narrowNBSP = "\u202f" // narrow no-break space
+)
+
+var locales = map[string]*Locale{
+	"en-US": {
+		Decimal: ".", Group: ",", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤#", PercentPattern: "#%",
+		Symbols: map[string]string{"USD": "$"},
+	},
+	"en-GB": {
+		Decimal: ".", Group: ",", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤#", PercentPattern: "#%",
+		Symbols: map[string]string{"GBP": "£"},
+	},
+	"en-CA": {
+		Decimal: ".", Group: ",", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤#", PercentPattern: "#%",
+		Symbols: map[string]string{"CAD": "$"},
+	},
+	"en-AU": {
+		Decimal: ".", Group: ",", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤#", PercentPattern: "#%",
+		Symbols: map[string]string{"AUD": "$"},
+	},
+	"en-IN": {
+		Decimal: ".", Group: ",", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 2, MinGroupingDigits: 1,
+		CurrencyPattern: "¤#", PercentPattern: "#%",
+		Symbols: map[string]string{"INR": "₹"},
+	},
+	"hi-IN": {
+		Decimal: ".", Group: ",", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 2, MinGroupingDigits: 1,
+		CurrencyPattern: "¤#", PercentPattern: "#%",
+		Symbols: map[string]string{"INR": "₹"},
+	},
+	"de-DE": {
+		Decimal: ",", Group: ".", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#" + nbsp + "%",
+	},
+	"de-AT": {
+		Decimal: ",", Group: nbsp, Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤" + nbsp + "#", PercentPattern: "#" + nbsp + "%",
+	},
+	"de-CH": {
+		Decimal: ".", Group: "’", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤" + nbsp + "#;¤-#", PercentPattern: "#%",
+	},
+	"fr-FR": {
+		Decimal: ",", Group: narrowNBSP, Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#" + narrowNBSP + "%",
+	},
+	"fr-CA": {
+		Decimal: ",", Group: nbsp, Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#" + nbsp + "%",
+		Symbols: map[string]string{"CAD": "$", "USD": "$" + nbsp + "US"},
+	},
+	"fr-CH": {
+		Decimal: ",", Group: narrowNBSP, Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#%",
+	},
+	"it-IT": {
+		Decimal: ",", Group: ".", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#%",
+	},
+	"es-ES": {
+		Decimal: ",", Group: ".", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 2,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#" + nbsp + "%",
+	},
+	"nl-NL": {
+		Decimal: ",", Group: ".", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤" + nbsp + "#;¤" + nbsp + "-#", PercentPattern: "#%",
+	},
+	"pt-BR": {
+		Decimal: ",", Group: ".", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤" + nbsp + "#", PercentPattern: "#%",
+	},
+	"pl-PL": {
+		Decimal: ",", Group: nbsp, Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 2,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#%",
+		Symbols: map[string]string{"PLN": "zł"},
+	},
+	"sv-SE": {
+		Decimal: ",", Group: nbsp, Minus: "−",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#" + nbsp + "%",
+		Symbols: map[string]string{"SEK": "kr"},
+	},
+	"ru-RU": {
+		Decimal: ",", Group: nbsp, Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "#" + nbsp + "¤", PercentPattern: "#" + nbsp + "%",
+		Symbols: map[string]string{"RUB": "₽"},
+	},
+	"ja-JP": {
+		Decimal: ".", Group: ",", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤#", PercentPattern: "#%",
+		Symbols: map[string]string{"JPY": "￥"},
+	},
+	"zh-CN": {
+		Decimal: ".", Group: ",", Minus: "-",
+		Grouping: 3, SecondaryGrouping: 3, MinGroupingDigits: 1,
+		CurrencyPattern: "¤#", PercentPattern: "#%",
+		Symbols: map[string]string{"CNY": "¥"},
+	},
+}
+
+// defaultRegions maps languages to the locale used for tags without a region
+// or with an unknown region.
+var defaultRegions = map[string]string{
+	"en": "en-US",
+	"hi": "hi-IN",
+	"de": "de-DE",
+	"fr": "fr-FR",
+	"it": "it-IT",
+	"es": "es-ES",
+	"nl": "nl-NL",
+	"pt": "pt-BR",
+	"pl": "pl-PL",
+	"sv": "sv-SE",
+	"ru": "ru-RU",
+	"ja": "ja-JP",
+	"zh": "zh-CN",
+}
+
+// currency is the locale independent data of a currency.
+type currency struct {
+	// symbol is used by locales that do not define their own symbol.
+	symbol string
+	// digits is the number of minor unit digits.
+	digits int
+}
+
+var currencies = map[string]currency{
+	"AUD": {symbol: "A$", digits: 2},
+	"BHD": {symbol: "BHD", digits: 3},
+	"BRL": {symbol: "R$", digits: 2},
+	"CAD": {symbol: "CA$", digits: 2},
+	"CHF": {symbol: "CHF", digits: 2},
+	"CLP": {symbol: "CLP", digits: 0},
+	"CNY": {symbol: "CN¥", digits: 2},
+	"EUR": {symbol: "€", digits: 2},
+	"GBP": {symbol: "£", digits: 2},
+	"HKD": {symbol: "HK$", digits: 2},
+	"INR": {symbol: "₹", digits: 2},
+	"ISK": {symbol: "ISK", digits: 0},
+	"JOD": {symbol: "JOD", digits: 3},
+	"JPY": {symbol: "JP¥", digits: 0},
+	"KRW": {symbol: "₩", digits: 0},
+	"KWD": {symbol: "KWD", digits: 3},
+	"MXN": {symbol: "MX$", digits: 2},
+	"NOK": {symbol: "NOK", digits: 2},
+	"NZD": {symbol: "NZ$", digits: 2},
+	"OMR": {symbol: "OMR", digits: 3},
+	"PLN": {symbol: "PLN", digits: 2},
+	"RUB": {symbol: "RUB", digits: 2},
+	"SEK": {symbol: "SEK", digits: 2},
+	"TND": {symbol: "TND", digits: 3},
+	"USD": {symbol: "US$", digits: 2},
+	"VND": {symbol: "₫", digits: 0},
+	"ZAR": {symbol: "ZAR", digits: 2},
+}
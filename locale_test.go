@@ -0,0 +1,114 @@
+package temple
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		args    []interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "en-US", locale: "en-US", args: []interface{}{1234567.891}, want: "1,234,567.891"},
+		{name: "de-DE", locale: "de-DE", args: []interface{}{1234.5}, want: "1.234,5"},
+		{name: "fr-CH", locale: "fr-CH", args: []interface{}{-1234.5}, want: "-1\u202f234,5"},
+		{name: "de-CH", locale: "de-CH", args: []interface{}{2, 1234567}, want: "1’234’567.00"},
+		{name: "hi-IN lakh", locale: "hi-IN", args: []interface{}{2, 1234567}, want: "12,34,567.00"},
+		{name: "hi-IN crore", locale: "hi-IN", args: []interface{}{100000000}, want: "10,00,00,000"},
+		{name: "es-ES minimum grouping", locale: "es-ES", args: []interface{}{1234}, want: "1234"},
+		{name: "es-ES grouping", locale: "es-ES", args: []interface{}{12345}, want: "12.345"},
+		{name: "sv-SE minus", locale: "sv-SE", args: []interface{}{-1234}, want: "−1\u00a0234"},
+		{name: "default places", locale: "en-US", args: []interface{}{"0.12345"}, want: "0.123"},
+		{name: "half-even", locale: "en-US", args: []interface{}{0, "2.5"}, want: "2"},
+		{name: "json.Number", locale: "en-US", args: []interface{}{json.Number("9007199254740993")}, want: "9,007,199,254,740,993"},
+		{name: "language only", locale: "de", args: []interface{}{1234.5}, want: "1.234,5"},
+		{name: "unknown region", locale: "de_LU", args: []interface{}{1234.5}, want: "1.234,5"},
+		{name: "unknown locale", locale: "xx-XX", args: []interface{}{1}, wantErr: true},
+		{name: "fractional places", locale: "en-US", args: []interface{}{1.5, 1}, wantErr: true},
+		{name: "not a number", locale: "en-US", args: []interface{}{"abc"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatNumber(tt.locale, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatNumber() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FormatNumber() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := FormatNumber("xx", 1); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("FormatNumber() error = %v, want ErrUnknownLocale", err)
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		code    string
+		v       interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "en-US", locale: "en-US", code: "USD", v: 1234.5, want: "$1,234.50"},
+		{name: "en-US negative", locale: "en-US", code: "USD", v: -1234.5, want: "-$1,234.50"},
+		{name: "en-US foreign", locale: "en-US", code: "EUR", v: 10, want: "€10.00"},
+		{name: "de-DE", locale: "de-DE", code: "EUR", v: "1234.5", want: "1.234,50\u00a0€"},
+		{name: "de-DE foreign", locale: "de-DE", code: "USD", v: 1, want: "1,00\u00a0US$"},
+		{name: "de-CH negative", locale: "de-CH", code: "CHF", v: -1234.5, want: "CHF-1’234.50"},
+		{name: "fr-CH", locale: "fr-CH", code: "CHF", v: 1234.5, want: "1\u202f234,50\u00a0CHF"},
+		{name: "nl-NL negative", locale: "nl-NL", code: "EUR", v: -5, want: "€\u00a0-5,00"},
+		{name: "hi-IN", locale: "hi-IN", code: "INR", v: 1234567.891, want: "₹12,34,567.89"},
+		{name: "JPY has no minor unit", locale: "ja-JP", code: "JPY", v: 1234.5, want: "￥1,234"},
+		{name: "KWD has three digits", locale: "en-US", code: "KWD", v: 1.23456, want: "KWD1.235"},
+		{name: "unknown code", locale: "en-US", code: "xts", v: 1, want: "XTS1.00"},
+		{name: "invalid code", locale: "en-US", code: "dollars", v: 1, wantErr: true},
+		{name: "unknown locale", locale: "tlh", code: "USD", v: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatCurrency(tt.locale, tt.code, tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatCurrency() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FormatCurrency() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		args   []interface{}
+		want   string
+	}{
+		{name: "en-US", locale: "en-US", args: []interface{}{0.25}, want: "25%"},
+		{name: "fr-CH", locale: "fr-CH", args: []interface{}{0.256}, want: "26%"},
+		{name: "fr-FR", locale: "fr-FR", args: []interface{}{0.256}, want: "26\u202f%"},
+		{name: "de-DE places", locale: "de-DE", args: []interface{}{1, 0.256}, want: "25,6\u00a0%"},
+		{name: "large", locale: "en-US", args: []interface{}{12.5}, want: "1,250%"},
+		{name: "negative", locale: "en-US", args: []interface{}{-0.5}, want: "-50%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatPercent(tt.locale, tt.args...)
+			if err != nil {
+				t.Fatalf("FormatPercent() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatPercent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package temple
+
+import (
+	"fmt"
+	"math"
+)
+
+// The math functions validate their arguments instead of returning NaN or
+// infinity. An argument outside of the domain of a function, such as the
+// square root of a negative number, results in an error wrapping ErrDomain,
+// and a result too large for a float64 in an error wrapping ErrOverflow.
+
+func Abs(x float64) float64 {
+	return math.Abs(x)
+}
+
+// absNumber is the Abs function of MathFuncs. Like the adapters of
+// NumbersFuncs, it accepts numbers of any kind, and like the other math
+// functions it rejects NaN and infinite values.
+func absNumber(x interface{}) (float64, error) {
+	f, err := mathArg(x)
+	if err != nil {
+		return 0, funcError("Abs", err, x)
+	}
+	return math.Abs(f), nil
+}
+
+// Pow returns x raised to the power y. A negative x requires an integer y,
+// and zero cannot be raised to a negative power.
+//		{{ Pow 2 0.5 }} -> 1.4142135623730951
+func Pow(x, y interface{}) (float64, error) {
+	f, err := pow(x, y)
+	return f, funcError("Pow", err, x, y)
+}
+
+func pow(x, y interface{}) (float64, error) {
+	a, err := mathArg(x)
+	if err != nil {
+		return 0, err
+	}
+	b, err := mathArg(y)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case a < 0 && b != math.Trunc(b):
+		return 0, fmt.Errorf("%w: negative number %v raised to a fractional power", ErrDomain, a)
+	case a == 0 && b < 0:
+		return 0, fmt.Errorf("%w: zero raised to a negative power", ErrDomain)
+	}
+	return mathResult(math.Pow(a, b))
+}
+
+// Sqrt returns the square root of x, which must not be negative.
+func Sqrt(x interface{}) (float64, error) {
+	f, err := mathArg(x)
+	if err == nil && f < 0 {
+		err = fmt.Errorf("%w: square root of negative number %v", ErrDomain, f)
+	}
+	if err != nil {
+		return 0, funcError("Sqrt", err, x)
+	}
+	return math.Sqrt(f), nil
+}
+
+// Log returns the natural logarithm of a positive number. The number may be
+// preceded by a base, which must be positive and not 1.
+//		{{ Log 1 }} -> 0
+//		{{ Log 10 1000 }} -> 3
+//		{{ .Size | Log 2 }}
+func Log(args ...interface{}) (float64, error) {
+	f, err := logarithm(args)
+	return f, funcError("Log", err, args...)
+}
+
+func logarithm(args []interface{}) (float64, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return 0, fmt.Errorf("wrong number of arguments: want 1 or 2, got %d", len(args))
+	}
+
+	x, err := mathArg(args[len(args)-1])
+	if err != nil {
+		return 0, err
+	}
+	if x <= 0 {
+		return 0, fmt.Errorf("%w: logarithm of non-positive number %v", ErrDomain, x)
+	}
+	if len(args) == 1 {
+		return math.Log(x), nil
+	}
+
+	base, err := mathArg(args[0])
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case base == 2:
+		return math.Log2(x), nil
+	case base == 10:
+		return math.Log10(x), nil
+	case base <= 0 || base == 1:
+		return 0, fmt.Errorf("%w: invalid logarithm base %v", ErrDomain, base)
+	}
+	return math.Log(x) / math.Log(base), nil
+}
+
+// Exp returns e raised to the power x.
+func Exp(x interface{}) (float64, error) {
+	f, err := mathArg(x)
+	if err == nil {
+		f, err = mathResult(math.Exp(f))
+	}
+	return f, funcError("Exp", err, x)
+}
+
+// Hypot returns the square root of x*x + y*y, such as the length of the
+// hypotenuse of a right triangle.
+func Hypot(x, y interface{}) (float64, error) {
+	a, err := mathArg(x)
+	if err != nil {
+		return 0, funcError("Hypot", err, x, y)
+	}
+	b, err := mathArg(y)
+	if err != nil {
+		return 0, funcError("Hypot", err, x, y)
+	}
+	f, err := mathResult(math.Hypot(a, b))
+	return f, funcError("Hypot", err, x, y)
+}
+
+// Sign returns -1, 0 or 1 depending on whether x is negative, zero or
+// positive.
+func Sign(x interface{}) (int, error) {
+	n, err := toNumber(x)
+	if err == nil && n.float && math.IsNaN(n.f) {
+		err = fmt.Errorf("%w: NaN", ErrDomain)
+	}
+	if err != nil {
+		return 0, funcError("Sign", err, x)
+	}
+	return compareNumbers(n, number{}), nil
+}
+
+// Larger returns the larger of two numbers, and Smaller returns the smaller
+// one. The numbers may be of any kind, and integers and big numbers are
+// compared exactly. The type of the result is the same as for Max.
+//		{{ Larger 3 2.5 }} -> 3
+func Larger(x, y interface{}) (interface{}, error) {
+	v, err := pick(1, x, y)
+	return v, funcError("Larger", err, x, y)
+}
+
+// Smaller returns the smaller of two numbers. See Larger.
+func Smaller(x, y interface{}) (interface{}, error) {
+	v, err := pick(-1, x, y)
+	return v, funcError("Smaller", err, x, y)
+}
+
+// pick returns the larger of two numbers if sign is positive and the smaller
+// one if sign is negative.
+func pick(sign int, x, y interface{}) (interface{}, error) {
+	a, err := mathNumber(x)
+	if err != nil {
+		return nil, err
+	}
+	b, err := mathNumber(y)
+	if err != nil {
+		return nil, err
+	}
+
+	float, isBig := a.float || b.float, hasBig([]number{a, b})
+	if compareNumbers(b, a) == sign {
+		return numberValue(b, float, isBig), nil
+	}
+	return numberValue(a, float, isBig), nil
+}
+
+// Clamp limits a number to the range from min to max. The number is the last
+// argument so that it can be piped. The type of the result is the same as for
+// Max. An error is returned if min is larger than max.
+//		{{ Clamp 0 100 120 }} -> 100
+//		{{ .Percent | Clamp 0 100 }}
+func Clamp(min, max, x interface{}) (interface{}, error) {
+	v, err := clamp(min, max, x)
+	return v, funcError("Clamp", err, min, max, x)
+}
+
+func clamp(min, max, x interface{}) (interface{}, error) {
+	var nums [3]number
+	for i, v := range []interface{}{min, max, x} {
+		n, err := mathNumber(v)
+		if err != nil {
+			return nil, err
+		}
+		nums[i] = n
+	}
+	lo, hi, n := nums[0], nums[1], nums[2]
+
+	if compareNumbers(lo, hi) > 0 {
+		return nil, fmt.Errorf("%w: minimum %v is larger than maximum %v", ErrDomain, min, max)
+	}
+
+	float, isBig := lo.float || hi.float || n.float, hasBig(nums[:])
+	switch {
+	case compareNumbers(n, lo) < 0:
+		return numberValue(lo, float, isBig), nil
+	case compareNumbers(n, hi) > 0:
+		return numberValue(hi, float, isBig), nil
+	}
+	return numberValue(n, float, isBig), nil
+}
+
+// compareNumbers returns -1, 0 or 1 depending on whether a is less than, equal
+// to or greater than b. Integers and big numbers are compared exactly.
+func compareNumbers(a, b number) int {
+	if a.r != nil || b.r != nil {
+		if x, y := a.rat(), b.rat(); x != nil && y != nil {
+			return x.Cmp(y)
+		}
+	}
+	if !a.float && !b.float {
+		switch {
+		case a.i < b.i:
+			return -1
+		case a.i > b.i:
+			return 1
+		}
+		return 0
+	}
+
+	switch f, g := a.float64(), b.float64(); {
+	case f < g:
+		return -1
+	case f > g:
+		return 1
+	}
+	return 0
+}
+
+// numberValue returns a number as a float64 if float is true and as an int
+// otherwise, matching the results of Max. If isBig is true, the number is
+// returned as a big number instead. See bigValue.
+func numberValue(n number, float, isBig bool) interface{} {
+	if r := n.rat(); isBig && r != nil {
+		return bigValue(r, float)
+	}
+	if float {
+		return n.float64()
+	}
+	return int(n.i)
+}
+
+// GCD returns the greatest common divisor of the integers. The result is never
+// negative. The arguments are the same as for IntSum.
+//		{{ GCD 12 18 }} -> 6
+func GCD(x interface{}, vals ...interface{}) (int64, error) {
+	return foldInts("GCD", gcd, x, vals)
+}
+
+// LCM returns the least common multiple of the integers. The result is never
+// negative, and is zero if any of the integers is zero. The arguments are the
+// same as for IntSum.
+//		{{ LCM 4 6 }} -> 12
+func LCM(x interface{}, vals ...interface{}) (int64, error) {
+	return foldInts("LCM", func(a, b int64) (int64, bool) {
+		if a == 0 || b == 0 {
+			return 0, true
+		}
+		g, ok := gcd(a, b)
+		if !ok {
+			return 0, false
+		}
+		l, ok := mulInt64(a/g, b)
+		if !ok || l == math.MinInt64 {
+			return 0, false
+		}
+		if l < 0 {
+			l = -l
+		}
+		return l, true
+	}, x, vals)
+}
+
+// gcd returns the non-negative greatest common divisor of a and b. It reports
+// false if the result is 2^63, which does not fit in an int64.
+func gcd(a, b int64) (int64, bool) {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == math.MinInt64 {
+		return 0, false
+	}
+	if a < 0 {
+		a = -a
+	}
+	return a, true
+}
+
+// mathNumber converts a single argument of the math functions. NaN and
+// infinite values are rejected.
+func mathNumber(v interface{}) (number, error) {
+	n, err := toNumber(v)
+	if err != nil {
+		return number{}, err
+	}
+	if n.float && (math.IsNaN(n.f) || math.IsInf(n.f, 0)) {
+		return number{}, fmt.Errorf("%w: %v is not a finite number", ErrDomain, n.f)
+	}
+	return n, nil
+}
+
+// mathArg converts a single argument of the math functions to a float64. See
+// mathNumber and number.checkedFloat64.
+func mathArg(v interface{}) (float64, error) {
+	n, err := mathNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.checkedFloat64()
+}
+
+// mathResult checks that the result of a math function is finite.
+func mathResult(f float64) (float64, error) {
+	switch {
+	case math.IsNaN(f):
+		return 0, ErrDomain
+	case math.IsInf(f, 0):
+		return 0, ErrOverflow
+	}
+	return f, nil
+}
@@ -0,0 +1,94 @@
+package temple
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	texttmpl "text/template"
+)
+
+func TestMath(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() (interface{}, error)
+		want    interface{}
+		wantErr error
+	}{
+		{name: "Abs", fn: func() (interface{}, error) { return Abs(-2.5), nil }, want: 2.5},
+		{name: "Abs any number", fn: func() (interface{}, error) { return absNumber("-2.5") }, want: 2.5},
+		{name: "Abs big", fn: func() (interface{}, error) { return absNumber(big.NewInt(-1 << 40)) }, want: float64(1 << 40)},
+		{name: "Abs NaN", fn: func() (interface{}, error) { return absNumber(math.NaN()) }, wantErr: ErrDomain},
+		{name: "Pow", fn: func() (interface{}, error) { return Pow(2, 10) }, want: 1024.0},
+		{name: "Pow negative base", fn: func() (interface{}, error) { return Pow(-2, 3) }, want: -8.0},
+		{name: "Pow negative base fraction", fn: func() (interface{}, error) { return Pow(-8, 1.0/3) }, wantErr: ErrDomain},
+		{name: "Pow zero negative", fn: func() (interface{}, error) { return Pow(0, -1) }, wantErr: ErrDomain},
+		{name: "Pow overflow", fn: func() (interface{}, error) { return Pow(10, 400) }, wantErr: ErrOverflow},
+		{name: "Sqrt", fn: func() (interface{}, error) { return Sqrt(16) }, want: 4.0},
+		{name: "Sqrt negative", fn: func() (interface{}, error) { return Sqrt(-1) }, wantErr: ErrDomain},
+		{name: "Log", fn: func() (interface{}, error) { return Log(math.E) }, want: 1.0},
+		{name: "Log base 10", fn: func() (interface{}, error) { return Log(10, 1000) }, want: 3.0},
+		{name: "Log base 2", fn: func() (interface{}, error) { return Log(2, 1024) }, want: 10.0},
+		{name: "Log base 4", fn: func() (interface{}, error) { return Log(4, 16) }, want: 2.0},
+		{name: "Log zero", fn: func() (interface{}, error) { return Log(0) }, wantErr: ErrDomain},
+		{name: "Log base 1", fn: func() (interface{}, error) { return Log(1, 5) }, wantErr: ErrDomain},
+		{name: "Log no arguments", fn: func() (interface{}, error) { return Log() }, wantErr: errors.New("")},
+		{name: "Exp", fn: func() (interface{}, error) { return Exp(0) }, want: 1.0},
+		{name: "Exp overflow", fn: func() (interface{}, error) { return Exp(1000) }, wantErr: ErrOverflow},
+		{name: "Hypot", fn: func() (interface{}, error) { return Hypot(3, 4) }, want: 5.0},
+		{name: "Hypot infinite", fn: func() (interface{}, error) { return Hypot(math.Inf(1), 4) }, wantErr: ErrDomain},
+		{name: "Sign negative", fn: func() (interface{}, error) { return Sign(-2.5) }, want: -1},
+		{name: "Sign zero", fn: func() (interface{}, error) { return Sign(0) }, want: 0},
+		{name: "Sign positive", fn: func() (interface{}, error) { return Sign(uint8(3)) }, want: 1},
+		{name: "Sign NaN", fn: func() (interface{}, error) { return Sign(math.NaN()) }, wantErr: ErrDomain},
+		{name: "Larger ints", fn: func() (interface{}, error) { return Larger(int64(3), uint(5)) }, want: 5},
+		{name: "Larger mixed", fn: func() (interface{}, error) { return Larger(3, 2.5) }, want: 3.0},
+		{name: "Larger exact", fn: func() (interface{}, error) { return Larger(int64(1<<53+1), int64(1<<53)) }, want: 1<<53 + 1},
+		{name: "Smaller", fn: func() (interface{}, error) { return Smaller("3", float32(2.5)) }, want: 2.5},
+		{name: "Smaller collection", fn: func() (interface{}, error) { return Smaller([]int{1}, 2) }, wantErr: ErrInvalidType},
+		{name: "Clamp above", fn: func() (interface{}, error) { return Clamp(0, 100, 120) }, want: 100},
+		{name: "Clamp below", fn: func() (interface{}, error) { return Clamp(0, 100, -5) }, want: 0},
+		{name: "Clamp inside", fn: func() (interface{}, error) { return Clamp(0, 1, 0.5) }, want: 0.5},
+		{name: "Clamp float bound", fn: func() (interface{}, error) { return Clamp(0, 1.5, 3) }, want: 1.5},
+		{name: "Clamp inverted", fn: func() (interface{}, error) { return Clamp(10, 1, 5) }, wantErr: ErrDomain},
+		{name: "GCD", fn: func() (interface{}, error) { return GCD(12, 18, []int{-27}) }, want: int64(3)},
+		{name: "GCD zero", fn: func() (interface{}, error) { return GCD(0, -5) }, want: int64(5)},
+		{name: "GCD overflow", fn: func() (interface{}, error) { return GCD(int64(math.MinInt64), 0) }, wantErr: ErrOverflow},
+		{name: "LCM", fn: func() (interface{}, error) { return LCM(4, -6, 10) }, want: int64(60)},
+		{name: "LCM zero", fn: func() (interface{}, error) { return LCM(4, 0) }, want: int64(0)},
+		{name: "LCM overflow", fn: func() (interface{}, error) { return LCM(int64(1)<<62, 3) }, wantErr: ErrOverflow},
+		{name: "LCM fraction", fn: func() (interface{}, error) { return LCM(4, 1.5) }, wantErr: errors.New("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if tt.wantErr.Error() != "" && !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestMathTemplate(t *testing.T) {
+	tmpl := texttmpl.Must(texttmpl.New("").Funcs(MathFuncs.Text()).Parse(
+		`{{ .Percent | Clamp 0 100 }} {{ Log 2 .Size }} {{ Pow 2 0.5 }}`,
+	))
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, map[string]interface{}{"Percent": 120.5, "Size": 1024}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := b.String(), "100 10 1.4142135623730951"; got != want {
+		t.Errorf("Execute() = %q, want %q", got, want)
+	}
+}
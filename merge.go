@@ -0,0 +1,196 @@
+package temple
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MergePolicy determines how conflicting function names are handled when
+// merging FuncMaps.
+type MergePolicy int
+
+const (
+	// MergeKeepLast keeps the last occurrence of a function. This is the
+	// behavior of MergeFuncMaps.
+	MergeKeepLast MergePolicy = iota
+	// MergeKeepFirst keeps the first occurrence of a function.
+	MergeKeepFirst
+	// MergeError fails the merge if a function is redefined.
+	MergeError
+	// MergeWarn keeps the last occurrence of a function and logs a warning.
+	MergeWarn
+)
+
+var mergePolicyNames = map[MergePolicy]string{
+	MergeKeepLast:  "last",
+	MergeKeepFirst: "first",
+	MergeError:     "error",
+	MergeWarn:      "warn",
+}
+
+// String returns the name of the policy as accepted by ParseMergePolicy.
+func (p MergePolicy) String() string {
+	if s, ok := mergePolicyNames[p]; ok {
+		return s
+	}
+	return fmt.Sprintf("MergePolicy(%d)", int(p))
+}
+
+// ParseMergePolicy parses the name of a merge policy. The valid names are
+// last, first, error and warn.
+func ParseMergePolicy(s string) (MergePolicy, error) {
+	for p, name := range mergePolicyNames {
+		if strings.EqualFold(s, name) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("temple: unknown merge policy %q", s)
+}
+
+// Override describes a function name that was defined by more than one of the
+// merged FuncMaps. Maps are identified by their position in the arguments of
+// Merge, with the base map at index 0.
+type Override struct {
+	Name string
+	// Previous is the index of the map that defined the function first.
+	Previous int
+	// Index is the index of the map that redefined the function.
+	Index int
+	// Replaced reports whether the function from Index replaced the one from
+	// Previous.
+	Replaced bool
+}
+
+func (o Override) String() string {
+	if o.Replaced {
+		return fmt.Sprintf("%s from map %d overridden by map %d", o.Name, o.Previous, o.Index)
+	}
+	return fmt.Sprintf("%s from map %d kept over map %d", o.Name, o.Previous, o.Index)
+}
+
+// ConflictError is returned when a merge using the MergeError policy finds
+// conflicting functions.
+type ConflictError struct {
+	Overrides []Override
+}
+
+func (e *ConflictError) Error() string {
+	names := make([]string, len(e.Overrides))
+	for i, o := range e.Overrides {
+		names[i] = o.Name
+	}
+	return fmt.Sprintf("temple: conflicting functions: %s", strings.Join(names, ", "))
+}
+
+// Merger combines FuncMaps according to a MergePolicy. Adding the same
+// function of DefaultRegistry under the same name more than once is not
+// considered a conflict. Other functions, such as closures, cannot be compared,
+// so redefining them always is.
+type Merger struct {
+	Policy MergePolicy
+	// Allow contains the names of functions that may be overridden regardless
+	// of the policy. These functions always keep their last occurrence, but
+	// are still included in the report.
+	Allow []string
+	// Warnf is used to report overrides with the MergeWarn policy. If nil, the
+	// standard logger is used.
+	Warnf func(format string, args ...interface{})
+}
+
+// Merge combines the FuncMaps into a new FuncMap and reports the function
+// names that were defined more than once. Unlike MergeFuncMaps, base is not
+// modified. With the MergeError policy, a *ConflictError is returned along with
+// the report if any unallowed function was redefined.
+func (m Merger) Merge(base FuncMap, additions ...FuncMap) (FuncMap, []Override, error) {
+	allowed := make(map[string]bool)
+	for _, name := range m.Allow {
+		allowed[name] = true
+	}
+
+	out := make(FuncMap, len(base))
+	from := make(map[string]int)
+	for k, v := range base {
+		out[k] = v
+		from[k] = 0
+	}
+
+	var overrides, conflicts []Override
+	for i, a := range additions {
+		for _, k := range sortedNames(a) {
+			v := a[k]
+			prev, ok := out[k]
+			if !ok {
+				out[k], from[k] = v, i+1
+				continue
+			}
+			if sameFunc(prev, v) {
+				if m.Policy != MergeKeepFirst {
+					out[k], from[k] = v, i+1
+				}
+				continue
+			}
+
+			o := Override{Name: k, Previous: from[k], Index: i + 1, Replaced: true}
+			if m.Policy == MergeKeepFirst && !allowed[k] {
+				o.Replaced = false
+			}
+			if m.Policy == MergeError && !allowed[k] {
+				conflicts = append(conflicts, o)
+			}
+			if o.Replaced {
+				out[k], from[k] = v, i+1
+			}
+			overrides = append(overrides, o)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return base, overrides, &ConflictError{Overrides: conflicts}
+	}
+
+	if m.Policy == MergeWarn {
+		warnf := m.Warnf
+		if warnf == nil {
+			warnf = log.Printf
+		}
+		for _, o := range overrides {
+			if !allowed[o.Name] {
+				warnf("temple: %s", o)
+			}
+		}
+	}
+
+	return out, overrides, nil
+}
+
+// MergeFuncMapsPolicy combines multiple FuncMaps according to the provided
+// policy. It is shorthand for Merger{Policy: policy}.Merge(base, additions...).
+func MergeFuncMapsPolicy(policy MergePolicy, base FuncMap, additions ...FuncMap) (FuncMap, []Override, error) {
+	return Merger{Policy: policy}.Merge(base, additions...)
+}
+
+// sameFunc determines if a and b are the same function of DefaultRegistry.
+// Functions are compared by their code, which closures created by the same
+// function literal share even if they capture different values, such as the
+// Include functions of different template sets. Such functions cannot be told
+// apart, so only functions registered under a single implementation are
+// considered the same.
+func sameFunc(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() != reflect.Func || vb.Kind() != reflect.Func || va.Pointer() != vb.Pointer() {
+		return false
+	}
+	return len(DefaultRegistry.lookupImpl(a)) == 1
+}
+
+func sortedNames(f FuncMap) []string {
+	names := make([]string, 0, len(f))
+	for k := range f {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,132 @@
+package temple
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	texttmpl "text/template"
+)
+
+func TestMerger_Merge(t *testing.T) {
+	upper := FuncMap{"Join": ToUpper, "ToUpper": ToUpper}
+	lower := FuncMap{"Join": ToLower, "ToLower": ToLower}
+
+	tests := []struct {
+		name      string
+		merger    Merger
+		wantJoin  interface{}
+		overrides []Override
+		wantErr   bool
+	}{
+		{
+			name:      "Keep last",
+			merger:    Merger{Policy: MergeKeepLast},
+			wantJoin:  ToLower,
+			overrides: []Override{{Name: "Join", Previous: 0, Index: 1, Replaced: true}},
+		},
+		{
+			name:      "Keep first",
+			merger:    Merger{Policy: MergeKeepFirst},
+			wantJoin:  ToUpper,
+			overrides: []Override{{Name: "Join", Previous: 0, Index: 1, Replaced: false}},
+		},
+		{
+			name:      "Error",
+			merger:    Merger{Policy: MergeError},
+			wantJoin:  ToUpper,
+			overrides: []Override{{Name: "Join", Previous: 0, Index: 1, Replaced: true}},
+			wantErr:   true,
+		},
+		{
+			name:      "Error with allowed override",
+			merger:    Merger{Policy: MergeError, Allow: []string{"Join"}},
+			wantJoin:  ToLower,
+			overrides: []Override{{Name: "Join", Previous: 0, Index: 1, Replaced: true}},
+		},
+		{
+			name:      "Warn",
+			merger:    Merger{Policy: MergeWarn, Warnf: func(string, ...interface{}) {}},
+			wantJoin:  ToLower,
+			overrides: []Override{{Name: "Join", Previous: 0, Index: 1, Replaced: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, overrides, err := tt.merger.Merge(upper, lower)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !sameFunc(got["Join"], tt.wantJoin) {
+				t.Errorf("Merge() kept the wrong Join")
+			}
+			if !reflect.DeepEqual(overrides, tt.overrides) {
+				t.Errorf("Merge() overrides = %v, want %v", overrides, tt.overrides)
+			}
+		})
+	}
+
+	if _, ok := upper["ToLower"]; ok {
+		t.Errorf("Merge() modified the base FuncMap")
+	}
+}
+
+func TestSameFunc(t *testing.T) {
+	a := TextIncludeFuncs(texttmpl.New("a"))
+	b := TextIncludeFuncs(texttmpl.New("b"))
+
+	fs := NewFileSystem(".")
+	if !sameFunc(ToUpper, ToUpper) || !sameFunc(fs.ReadFile, fs.ReadFile) {
+		t.Errorf("sameFunc() = false for the same function")
+	}
+	if sameFunc(ToUpper, ToLower) || sameFunc(a["Include"], b["Include"]) {
+		t.Errorf("sameFunc() = true for different functions")
+	}
+
+	_, overrides, err := Merger{Policy: MergeError}.Merge(a, b)
+	if err == nil || len(overrides) != 2 {
+		t.Errorf("Merge() = %v, %v, want conflicts for Include and Tpl", overrides, err)
+	}
+}
+
+func TestMerger_Merge_warn(t *testing.T) {
+	var warnings []string
+	m := Merger{Policy: MergeWarn, Warnf: func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}}
+
+	_, _, err := m.Merge(FuncMap{"Join": ToUpper}, FuncMap{"Join": ToUpper}, FuncMap{"Join": ToLower})
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	want := []string{"temple: Join from map 1 overridden by map 2"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("Merge() warnings = %q, want %q", warnings, want)
+	}
+}
+
+func TestMergeFuncMapsPolicy_error(t *testing.T) {
+	_, _, err := MergeFuncMapsPolicy(MergeError, FuncMap{"A": ToUpper, "B": ToUpper}, FuncMap{"A": ToLower, "B": ToUpper})
+
+	var ce *ConflictError
+	if !errors.As(err, &ce) {
+		t.Fatalf("MergeFuncMapsPolicy() error = %v, want *ConflictError", err)
+	}
+	if len(ce.Overrides) != 1 || ce.Overrides[0].Name != "A" {
+		t.Errorf("MergeFuncMapsPolicy() conflicts = %v, want only A", ce.Overrides)
+	}
+}
+
+func TestParseMergePolicy(t *testing.T) {
+	for _, p := range []MergePolicy{MergeKeepLast, MergeKeepFirst, MergeError, MergeWarn} {
+		got, err := ParseMergePolicy(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParseMergePolicy(%q) = %v, %v, want %v", p.String(), got, err, p)
+		}
+	}
+
+	if _, err := ParseMergePolicy("newest"); err == nil {
+		t.Errorf("ParseMergePolicy() expected error for unknown policy")
+	}
+}
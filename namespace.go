@@ -0,0 +1,228 @@
+package temple
+
+// PrefixFuncMap returns a copy of f with every function name prefixed with
+// prefix. For example, PrefixFuncMap("str_", StringsFuncs) contains str_Join.
+func PrefixFuncMap(prefix string, f FuncMap) FuncMap {
+	out := make(FuncMap, len(f))
+	for k, v := range f {
+		out[prefix+k] = v
+	}
+	return out
+}
+
+// NamespaceFuncMap returns a FuncMap containing a single function that takes
+// no arguments and returns ns. The methods of ns can then be called from a
+// template through the function name, such as
+//		{{ Strings.Join ", " .Names }}
+func NamespaceFuncMap(name string, ns interface{}) FuncMap {
+	return FuncMap{name: func() interface{} { return ns }}
+}
+
+// NamespaceFuncs maps the namespaces Strings, Numbers, Conversion and
+// Collection to the StringsFuncs, NumbersFuncs, ConversionFuncs and
+// CollectionFuncs respectively.
+var NamespaceFuncs FuncMap = MergeFuncMaps(
+	NamespaceFuncMap("Strings", StringsNamespace{}),
+	NamespaceFuncMap("Numbers", NumbersNamespace{}),
+	NamespaceFuncMap("Conversion", ConversionNamespace{}),
+	NamespaceFuncMap("Collection", CollectionNamespace{}),
+)
+
+// StringsNamespace exposes the functions in StringsFuncs as methods.
+type StringsNamespace struct{}
+
+// Commas calls Commas.
+func (StringsNamespace) Commas(v interface{}) (string, error) { return Commas(v) }
+
+// IsNumeric calls IsNumeric.
+func (StringsNamespace) IsNumeric(s string) bool { return IsNumeric(s) }
+
+// Join calls Join.
+func (StringsNamespace) Join(sep string, a interface{}) (string, error) { return Join(sep, a) }
+
+// FormatMask calls FormatMask.
+func (StringsNamespace) FormatMask(mask string, str string) (string, error) {
+	return FormatMask(mask, str)
+}
+
+// NumbersNamespace exposes the functions in NumbersFuncs as methods.
+type NumbersNamespace struct{}
+
+// Max calls Max.
+func (NumbersNamespace) Max(arg1 interface{}, arg2 ...interface{}) (interface{}, error) {
+	return Max(arg1, arg2...)
+}
+
+// Min calls Min.
+func (NumbersNamespace) Min(arg1 interface{}, arg2 ...interface{}) (interface{}, error) {
+	return Min(arg1, arg2...)
+}
+
+// IntMax calls IntMax.
+func (NumbersNamespace) IntMax(arg1 interface{}, arg2 ...interface{}) (int, error) {
+	return IntMax(arg1, arg2...)
+}
+
+// IntMin calls IntMin.
+func (NumbersNamespace) IntMin(arg1 interface{}, arg2 ...interface{}) (int, error) {
+	return IntMin(arg1, arg2...)
+}
+
+// FloatMax calls FloatMax.
+func (NumbersNamespace) FloatMax(arg1 interface{}, arg2 ...interface{}) (float64, error) {
+	return FloatMax(arg1, arg2...)
+}
+
+// FloatMin calls FloatMin.
+func (NumbersNamespace) FloatMin(arg1 interface{}, arg2 ...interface{}) (float64, error) {
+	return FloatMin(arg1, arg2...)
+}
+
+// Ceil calls the Ceil function of NumbersFuncs.
+func (NumbersNamespace) Ceil(x interface{}) (float64, error) { return ceilNumber(x) }
+
+// Floor calls the Floor function of NumbersFuncs.
+func (NumbersNamespace) Floor(x interface{}) (float64, error) { return floorNumber(x) }
+
+// Mod calls the Mod function of NumbersFuncs.
+func (NumbersNamespace) Mod(x, y interface{}) (float64, error) { return modNumbers(x, y) }
+
+// Sum calls the Sum function of NumbersFuncs.
+func (NumbersNamespace) Sum(x interface{}, vals ...interface{}) (float64, error) {
+	return sumNumbers(x, vals...)
+}
+
+// Diff calls the Diff function of NumbersFuncs.
+func (NumbersNamespace) Diff(x interface{}, vals ...interface{}) (float64, error) {
+	return diffNumbers(x, vals...)
+}
+
+// Mul calls the Mul function of NumbersFuncs.
+func (NumbersNamespace) Mul(x interface{}, vals ...interface{}) (float64, error) {
+	return mulNumbers(x, vals...)
+}
+
+// Div calls the Div function of NumbersFuncs.
+func (NumbersNamespace) Div(x interface{}, vals ...interface{}) (float64, error) {
+	return divNumbers(x, vals...)
+}
+
+// IntSum calls IntSum.
+func (NumbersNamespace) IntSum(x interface{}, vals ...interface{}) (int64, error) {
+	return IntSum(x, vals...)
+}
+
+// IntDiff calls IntDiff.
+func (NumbersNamespace) IntDiff(x interface{}, vals ...interface{}) (int64, error) {
+	return IntDiff(x, vals...)
+}
+
+// IntMul calls IntMul.
+func (NumbersNamespace) IntMul(x interface{}, vals ...interface{}) (int64, error) {
+	return IntMul(x, vals...)
+}
+
+// IntDiv calls IntDiv.
+func (NumbersNamespace) IntDiv(x, y interface{}) (int64, error) { return IntDiv(x, y) }
+
+// IntMod calls IntMod.
+func (NumbersNamespace) IntMod(x, y interface{}) (int64, error) { return IntMod(x, y) }
+
+// IntPow calls IntPow.
+func (NumbersNamespace) IntPow(x, n interface{}) (int64, error) { return IntPow(x, n) }
+
+// IntAbs calls IntAbs.
+func (NumbersNamespace) IntAbs(x interface{}) (int64, error) { return IntAbs(x) }
+
+// Round calls Round.
+func (NumbersNamespace) Round(places int, args ...interface{}) (float64, error) {
+	return Round(places, args...)
+}
+
+// Truncate calls Truncate.
+func (NumbersNamespace) Truncate(places int, x interface{}) (float64, error) {
+	return Truncate(places, x)
+}
+
+// RoundSig calls RoundSig.
+func (NumbersNamespace) RoundSig(figures int, args ...interface{}) (float64, error) {
+	return RoundSig(figures, args...)
+}
+
+// FormatFixed calls FormatFixed.
+func (NumbersNamespace) FormatFixed(places int, args ...interface{}) (string, error) {
+	return FormatFixed(places, args...)
+}
+
+// FormatNumber calls FormatNumber.
+func (NumbersNamespace) FormatNumber(locale string, args ...interface{}) (string, error) {
+	return FormatNumber(locale, args...)
+}
+
+// FormatCurrency calls FormatCurrency.
+func (NumbersNamespace) FormatCurrency(locale, code string, v interface{}) (string, error) {
+	return FormatCurrency(locale, code, v)
+}
+
+// FormatPercent calls FormatPercent.
+func (NumbersNamespace) FormatPercent(locale string, args ...interface{}) (string, error) {
+	return FormatPercent(locale, args...)
+}
+
+// HumanBytes calls HumanBytes.
+func (NumbersNamespace) HumanBytes(args ...interface{}) (string, error) { return HumanBytes(args...) }
+
+// HumanDuration calls HumanDuration.
+func (NumbersNamespace) HumanDuration(args ...interface{}) (string, error) {
+	return HumanDuration(args...)
+}
+
+// CompactNumber calls CompactNumber.
+func (NumbersNamespace) CompactNumber(args ...interface{}) (string, error) {
+	return CompactNumber(args...)
+}
+
+// Ordinal calls Ordinal.
+func (NumbersNamespace) Ordinal(v interface{}) (string, error) { return Ordinal(v) }
+
+// NumberToWords calls NumberToWords.
+func (NumbersNamespace) NumberToWords(v interface{}) (string, error) { return NumberToWords(v) }
+
+// ConversionNamespace exposes the functions in ConversionFuncs as methods.
+type ConversionNamespace struct{}
+
+// ToInt calls ToInt.
+func (ConversionNamespace) ToInt(v interface{}) (int, error) { return ToInt(v) }
+
+// ToFloat64 calls ToFloat64.
+func (ConversionNamespace) ToFloat64(v interface{}) (float64, error) { return ToFloat64(v) }
+
+// ToString calls ToString.
+func (ConversionNamespace) ToString(v interface{}) (string, error) { return ToString(v) }
+
+// ToIntSlice calls ToIntSlice.
+func (ConversionNamespace) ToIntSlice(vals []interface{}) ([]int, error) { return ToIntSlice(vals) }
+
+// ToFloat64Slice calls ToFloat64Slice.
+func (ConversionNamespace) ToFloat64Slice(vals []interface{}) ([]float64, error) {
+	return ToFloat64Slice(vals)
+}
+
+// ToStringSlice calls ToStringSlice.
+func (ConversionNamespace) ToStringSlice(vals []interface{}) ([]string, error) {
+	return ToStringSlice(vals)
+}
+
+// CollectionNamespace exposes the functions in CollectionFuncs as methods.
+type CollectionNamespace struct{}
+
+// NewList calls NewList.
+func (CollectionNamespace) NewList(vals ...interface{}) List { return NewList(vals...) }
+
+// NewSet calls NewSet.
+func (CollectionNamespace) NewSet(vals ...interface{}) Set { return NewSet(vals...) }
+
+// Contains calls Contains.
+func (CollectionNamespace) Contains(v interface{}, c interface{}) (bool, error) {
+	return Contains(v, c)
+}
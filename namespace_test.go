@@ -0,0 +1,71 @@
+package temple
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	texttmpl "text/template"
+)
+
+func TestPrefixFuncMap(t *testing.T) {
+	got := PrefixFuncMap("str_", StringsFuncs)
+	if len(got) != len(StringsFuncs) {
+		t.Fatalf("PrefixFuncMap() has %d functions, want %d", len(got), len(StringsFuncs))
+	}
+	if _, ok := got["str_Join"]; !ok {
+		t.Errorf("PrefixFuncMap() is missing str_Join")
+	}
+	if _, ok := StringsFuncs["str_Join"]; ok {
+		t.Errorf("PrefixFuncMap() modified its argument")
+	}
+}
+
+// TestNamespaces verifies that every namespace has a method for each function
+// in its FuncMap, and that the methods have the same signatures.
+func TestNamespaces(t *testing.T) {
+	tests := []struct {
+		name  string
+		ns    interface{}
+		funcs FuncMap
+	}{
+		{name: "Strings", ns: StringsNamespace{}, funcs: StringsFuncs},
+		{name: "Numbers", ns: NumbersNamespace{}, funcs: NumbersFuncs},
+		{name: "Conversion", ns: ConversionNamespace{}, funcs: ConversionFuncs},
+		{name: "Collection", ns: CollectionNamespace{}, funcs: CollectionFuncs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := reflect.ValueOf(tt.ns)
+			if v.NumMethod() != len(tt.funcs) {
+				t.Errorf("%s has %d methods, want %d", tt.name, v.NumMethod(), len(tt.funcs))
+			}
+			for name, fn := range tt.funcs {
+				m := v.MethodByName(name)
+				if !m.IsValid() {
+					t.Errorf("%s is missing method %s", tt.name, name)
+					continue
+				}
+				if m.Type() != reflect.TypeOf(fn) {
+					t.Errorf("%s.%s has type %v, want %v", tt.name, name, m.Type(), reflect.TypeOf(fn))
+				}
+			}
+			if _, ok := NamespaceFuncs[tt.name]; !ok {
+				t.Errorf("NamespaceFuncs is missing %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestNamespaceFuncs_execute(t *testing.T) {
+	tmpl := texttmpl.Must(texttmpl.New("").Funcs(NamespaceFuncs.Text()).Parse(
+		`{{ Strings.Join ", " .Names }} {{ Numbers.Sum 1 2 }} {{ Collection.Contains "b" (Collection.NewList "a" "b") }}`,
+	))
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, map[string]interface{}{"Names": []string{"a", "b"}}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := b.String(), "a, b 3 true"; got != want {
+		t.Errorf("Execute() = %q, want %q", got, want)
+	}
+}
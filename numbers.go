@@ -1,51 +1,64 @@
 package temple
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
 	"math"
+	"math/big"
+	"reflect"
+	"strconv"
 )
 
+// Max returns the largest of the provided numbers. The arguments may be
+// numbers of any kind or collections of numbers, such as slices, arrays, Lists
+// and Sets. If any of the numbers is a float, the result is a float64.
+// Otherwise, the result is an int. Big numbers, including uint64 values above
+// the range of an int64, are compared exactly, and if any of the numbers is
+// big, the result is a *big.Int if all of the numbers are integers, and a
+// Decimal otherwise.
 func Max(arg1 interface{}, arg2 ...interface{}) (interface{}, error) {
-	switch v := arg1.(type) {
-	case int, []int:
-		return IntMax(arg1, arg2...)
-	case float64, []float64:
-		return FloatMax(arg1, arg2...)
-	case []interface{}:
-		if len(v) == 0 {
-			return nil, errors.New("empty slice provided")
-		}
-		switch v[0].(type) {
-		case int:
-			return IntMax(arg1, arg2...)
-		case float64:
-			return FloatMax(arg1, arg2...)
-		}
-	case List:
-		if len(v) == 0 {
-			return nil, errors.New("empty List provided")
-		}
-		switch v[0].(type) {
-		case int:
-			return IntMax(arg1, arg2...)
-		case float64:
-			return FloatMax(arg1, arg2...)
-		}
-	case Set:
-		if len(v) == 0 {
-			return nil, errors.New("empty set provided")
+	v, err := extreme(1, arg1, arg2...)
+	if err != nil {
+		return nil, funcError("Max", err, append([]interface{}{arg1}, arg2...)...)
+	}
+	return v, nil
+}
+
+// Min returns the smallest of the provided numbers. See Max for the accepted
+// arguments and the type of the result.
+func Min(arg1 interface{}, arg2 ...interface{}) (interface{}, error) {
+	v, err := extreme(-1, arg1, arg2...)
+	if err != nil {
+		return nil, funcError("Min", err, append([]interface{}{arg1}, arg2...)...)
+	}
+	return v, nil
+}
+
+// extreme returns the largest number if sign is positive and the smallest
+// number if sign is negative.
+func extreme(sign int, arg1 interface{}, arg2 ...interface{}) (interface{}, error) {
+	nums, float, err := parseNumbers(append([]interface{}{arg1}, arg2...))
+	if err != nil {
+		return nil, err
+	} else if len(nums) == 0 {
+		return nil, ErrEmpty
+	}
+
+	m := nums[0]
+	for _, n := range nums[1:] {
+		if compareNumbers(n, m) == sign {
+			m = n
 		}
-		return IntMax(arg1, arg2...)
 	}
-	return nil, errors.New("invalid type")
+	return numberValue(m, float, hasBig(nums)), nil
 }
 
 func IntMax(arg1 interface{}, arg2 ...interface{}) (int, error) {
 	vals, err := parseIntArgs(arg1, arg2...)
 	if err != nil {
-		return 0, err
+		return 0, funcError("IntMax", err, append([]interface{}{arg1}, arg2...)...)
 	} else if len(vals) == 0 {
-		return 0, errors.New("empty slice")
+		return 0, funcError("IntMax", ErrEmpty, append([]interface{}{arg1}, arg2...)...)
 	}
 
 	max := vals[0]
@@ -60,9 +73,9 @@ func IntMax(arg1 interface{}, arg2 ...interface{}) (int, error) {
 func IntMin(arg1 interface{}, arg2 ...interface{}) (int, error) {
 	vals, err := parseIntArgs(arg1, arg2...)
 	if err != nil {
-		return 0, err
+		return 0, funcError("IntMin", err, append([]interface{}{arg1}, arg2...)...)
 	} else if len(vals) == 0 {
-		return 0, errors.New("empty slice")
+		return 0, funcError("IntMin", ErrEmpty, append([]interface{}{arg1}, arg2...)...)
 	}
 
 	min := vals[0]
@@ -74,41 +87,41 @@ func IntMin(arg1 interface{}, arg2 ...interface{}) (int, error) {
 	return min, nil
 }
 
+// parseIntArgs flattens the arguments into ints. Floats are truncated, and
+// numbers outside the range of an int are an error.
 func parseIntArgs(arg1 interface{}, arg2 ...interface{}) ([]int, error) {
-	var vals []int
-	var err error
+	nums, _, err := parseNumbers(append([]interface{}{arg1}, arg2...))
+	if err != nil {
+		return nil, err
+	}
 
-	switch v := arg1.(type) {
-	case int:
-		vals, err = ToIntSlice(append([]interface{}{arg1}, arg2...))
-	case List:
-		vals, err = ToIntSlice(v)
-	case Set:
-		vals = make([]int, len(v))
-		for k := range v {
-			val, err := ToInt(k)
-			if err != nil {
-				return nil, err
+	vals := make([]int, len(nums))
+	for i, n := range nums {
+		switch {
+		case n.r != nil:
+			q := new(big.Int).Quo(n.r.Num(), n.r.Denom())
+			if !q.IsInt64() || int64(int(q.Int64())) != q.Int64() {
+				return nil, ErrOverflow
+			}
+			vals[i] = int(q.Int64())
+		case n.float:
+			if !(n.f >= -maxIntFloat && n.f < maxIntFloat) {
+				return nil, ErrOverflow
 			}
-			vals = append(vals, val)
+			vals[i] = int(n.f)
+		default:
+			vals[i] = int(n.i)
 		}
-	case []interface{}:
-		vals, err = ToIntSlice(v)
-	case []int:
-		vals = v
-	default:
-		err = errors.New("invalid type")
 	}
-
-	return vals, err
+	return vals, nil
 }
 
 func FloatMax(arg1 interface{}, arg2 ...interface{}) (float64, error) {
 	vals, err := parseFloatArgs(arg1, arg2...)
 	if err != nil {
-		return 0, err
+		return 0, funcError("FloatMax", err, append([]interface{}{arg1}, arg2...)...)
 	} else if len(vals) == 0 {
-		return 0, errors.New("empty slice")
+		return 0, funcError("FloatMax", ErrEmpty, append([]interface{}{arg1}, arg2...)...)
 	}
 
 	max := vals[0]
@@ -121,9 +134,9 @@ func FloatMax(arg1 interface{}, arg2 ...interface{}) (float64, error) {
 func FloatMin(arg1 interface{}, arg2 ...interface{}) (float64, error) {
 	vals, err := parseFloatArgs(arg1, arg2...)
 	if err != nil {
-		return 0, err
+		return 0, funcError("FloatMin", err, append([]interface{}{arg1}, arg2...)...)
 	} else if len(vals) == 0 {
-		return 0, errors.New("empty slice")
+		return 0, funcError("FloatMin", ErrEmpty, append([]interface{}{arg1}, arg2...)...)
 	}
 
 	min := vals[0]
@@ -133,24 +146,238 @@ func FloatMin(arg1 interface{}, arg2 ...interface{}) (float64, error) {
 	return min, nil
 }
 
+// parseFloatArgs flattens the arguments into float64s. See number.checkedFloat64
+// for the big numbers that are accepted.
 func parseFloatArgs(arg1 interface{}, arg2 ...interface{}) ([]float64, error) {
-	var vals []float64
+	nums, _, err := parseNumbers(append([]interface{}{arg1}, arg2...))
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]float64, len(nums))
+	for i, n := range nums {
+		if vals[i], err = n.checkedFloat64(); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// number is a numeric argument. Integers are kept as int64 so that they can be
+// compared without a loss of precision. Big numbers other than integers that
+// fit in an int64 are kept exactly in r, and f holds the nearest float64.
+type number struct {
+	i     int64
+	f     float64
+	float bool
+	r     *big.Rat
+}
+
+func (n number) float64() float64 {
+	if n.float || n.r != nil {
+		return n.f
+	}
+	return float64(n.i)
+}
+
+// checkedFloat64 returns the number as a float64. Fractions are rounded to the
+// nearest float64, but ErrOverflow is returned instead of rounding a big
+// integer that a float64 cannot represent exactly, or a big number outside
+// the range of a float64.
+func (n number) checkedFloat64() (float64, error) {
+	if n.r == nil {
+		return n.float64(), nil
+	}
+	f, exact := n.r.Float64()
+	if math.IsInf(f, 0) || (n.r.IsInt() && !exact) {
+		return 0, ErrOverflow
+	}
+	return f, nil
+}
+
+// rat returns the exact value of the number. Floats are converted from their
+// shortest decimal representation, like Dec does. It returns nil for NaN and
+// infinite values.
+func (n number) rat() *big.Rat {
+	switch {
+	case n.r != nil:
+		return n.r
+	case !n.float:
+		return new(big.Rat).SetInt64(n.i)
+	case math.IsNaN(n.f) || math.IsInf(n.f, 0):
+		return nil
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(n.f, 'g', -1, 64))
+	return r
+}
+
+// hasBig reports whether any of the numbers is big, in which case results
+// should be computed and returned as big numbers.
+func hasBig(nums []number) bool {
+	for _, n := range nums {
+		if n.r != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// bigValue returns an exact result as a *big.Int if float is false and as a
+// Decimal otherwise.
+func bigValue(r *big.Rat, float bool) interface{} {
+	if !float && r.IsInt() {
+		return new(big.Int).Set(r.Num())
+	}
+	return ratDecimal(r)
+}
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// parseNumbers flattens the arguments into numbers. Each argument may be a
+// value of any integer or float kind, a json.Number, a *big.Int, *big.Float or
+// *big.Rat, a numeric string, or a slice, array, List or Set of these values.
+// Collections may be nested. The second result reports whether any of the
+// numbers is a float, in which case all of the numbers should be promoted to
+// floats.
+func parseNumbers(args []interface{}) ([]number, bool, error) {
+	var nums []number
 	var err error
+	for _, a := range args {
+		nums, err = appendNumbers(nums, reflect.ValueOf(a))
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	for _, n := range nums {
+		if n.float {
+			return nums, true, nil
+		}
+	}
+	return nums, false, nil
+}
+
+func appendNumbers(nums []number, v reflect.Value) ([]number, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, ErrInvalidType
+		}
+		if n, ok := bigNumber(v); ok {
+			return append(nums, n), nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, ErrInvalidType
+	}
+
+	if v.Type() == jsonNumberType {
+		return appendNumberString(nums, v.String())
+	}
 
-	switch v := arg1.(type) {
-	case float64, float32, int:
-		vals, err = ToFloat64Slice(append([]interface{}{arg1}, arg2...))
-	case List:
-		vals, err = ToFloat64Slice(v)
-	case []interface{}:
-		vals, err = ToFloat64Slice(v)
-	case []float64:
-		vals = v
-	default:
-		err = errors.New("invalid type")
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return append(nums, number{i: v.Int()}), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u > math.MaxInt64 {
+			return append(nums, bigIntNumber(new(big.Int).SetUint64(u))), nil
+		}
+		return append(nums, number{i: int64(v.Uint())}), nil
+	case reflect.Float32, reflect.Float64:
+		return append(nums, number{f: v.Float(), float: true}), nil
+	case reflect.String:
+		return appendNumberString(nums, v.String())
+	case reflect.Slice, reflect.Array:
+		var err error
+		for i := 0; i < v.Len(); i++ {
+			if nums, err = appendNumbers(nums, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return nums, nil
+	case reflect.Map:
+		// The elements of a Set are its keys.
+		if v.Type().Elem().Kind() != reflect.Bool {
+			return nil, ErrInvalidType
+		}
+		var err error
+		for _, k := range v.MapKeys() {
+			if nums, err = appendNumbers(nums, k); err != nil {
+				return nil, err
+			}
+		}
+		return nums, nil
 	}
 
-	return vals, err
+	return nil, ErrInvalidType
+}
+
+func appendNumberString(nums []number, s string) ([]number, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return append(nums, number{i: i}), nil
+	}
+	if i, ok := new(big.Int).SetString(s, 10); ok {
+		return append(nums, bigIntNumber(i)), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, ErrNotNumeric
+	}
+	return append(nums, number{f: f, float: true}), nil
+}
+
+// bigNumber converts a pointer to a big.Int, big.Float or big.Rat. Integers
+// that fit in an int64 are kept as int64, and other values are kept exactly.
+// Infinite big.Floats are converted to infinite float64s.
+func bigNumber(v reflect.Value) (number, bool) {
+	if v.Kind() != reflect.Ptr || !v.CanInterface() {
+		return number{}, false
+	}
+
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		return bigIntNumber(x), true
+	case *big.Float:
+		f, _ := x.Float64()
+		if x.IsInf() {
+			return number{f: f, float: true}, true
+		}
+		r, _ := x.Rat(nil)
+		return number{f: f, float: true, r: r}, true
+	case *big.Rat:
+		f, _ := x.Float64()
+		return number{f: f, float: true, r: new(big.Rat).Set(x)}, true
+	}
+	return number{}, false
+}
+
+func bigIntNumber(x *big.Int) number {
+	if x.IsInt64() {
+		return number{i: x.Int64()}
+	}
+	r := new(big.Rat).SetInt(x)
+	f, _ := r.Float64()
+	return number{f: f, r: r}
+}
+
+// toNumber converts a single numeric argument. See parseNumbers for the
+// accepted types. Collections are not accepted.
+func toNumber(v interface{}) (number, error) {
+	rv := reflect.ValueOf(v)
+	e := rv
+	for (e.Kind() == reflect.Ptr || e.Kind() == reflect.Interface) && !e.IsNil() {
+		e = e.Elem()
+	}
+	switch e.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return number{}, ErrInvalidType
+	}
+
+	nums, err := appendNumbers(nil, rv)
+	if err != nil {
+		return number{}, err
+	}
+	return nums[0], nil
 }
 
 func Ceil(f float64) float64 {
@@ -161,12 +388,13 @@ func Floor(f float64) float64 {
 	return math.Floor(f)
 }
 
-func Mod(x float64, y float64) float64 {
-	return math.Mod(x, y)
-}
-
-func Abs(x float64) float64 {
-	return math.Abs(x)
+// Mod returns the floating point remainder of x/y. An error is returned if y
+// is zero.
+func Mod(x float64, y float64) (float64, error) {
+	if y == 0 {
+		return 0, funcError("Mod", ErrDivisionByZero, x, y)
+	}
+	return math.Mod(x, y), nil
 }
 
 func Sum(x float64, vals ...float64) float64 {
@@ -190,13 +418,190 @@ func Mul(x float64, vals ...float64) float64 {
 	return x
 }
 
-func Div(x float64, vals ...float64) float64 {
+// Div divides x by each of the values in turn. An error is returned if any of
+// the values is zero.
+func Div(x float64, vals ...float64) (float64, error) {
+	q := x
 	for _, v := range vals {
 		if v == 0 {
-			panic("temple: division by zero")
+			args := []interface{}{x}
+			for _, v := range vals {
+				args = append(args, v)
+			}
+			return 0, funcError("Div", ErrDivisionByZero, args...)
 		}
 
-		x /= v
+		q /= v
 	}
-	return x
+	return q, nil
+}
+
+// The Ceil, Floor, Mod, Sum, Diff, Mul and Div functions of NumbersFuncs are
+// adapters accepting numbers of any kind, like Max, so that templates can pass
+// the numbers decoded in every NumberMode. The arguments are converted to
+// float64, and integers too large to be represented exactly are an error.
+
+func ceilNumber(x interface{}) (float64, error) {
+	f, err := floatArg(x)
+	if err != nil {
+		return 0, funcError("Ceil", err, x)
+	}
+	return Ceil(f), nil
+}
+
+func floorNumber(x interface{}) (float64, error) {
+	f, err := floatArg(x)
+	if err != nil {
+		return 0, funcError("Floor", err, x)
+	}
+	return Floor(f), nil
+}
+
+func modNumbers(x, y interface{}) (float64, error) {
+	a, err := floatArg(x)
+	if err != nil {
+		return 0, funcError("Mod", err, x, y)
+	}
+	b, err := floatArg(y)
+	if err != nil {
+		return 0, funcError("Mod", err, x, y)
+	}
+	if b == 0 {
+		return 0, funcError("Mod", ErrDivisionByZero, x, y)
+	}
+	return math.Mod(a, b), nil
+}
+
+func sumNumbers(x interface{}, vals ...interface{}) (float64, error) {
+	return foldFloats("Sum", func(a, b float64) float64 { return a + b }, x, vals)
+}
+
+func diffNumbers(x interface{}, vals ...interface{}) (float64, error) {
+	return foldFloats("Diff", func(a, b float64) float64 { return a - b }, x, vals)
+}
+
+func mulNumbers(x interface{}, vals ...interface{}) (float64, error) {
+	return foldFloats("Mul", func(a, b float64) float64 { return a * b }, x, vals)
+}
+
+func divNumbers(x interface{}, vals ...interface{}) (float64, error) {
+	nums, err := parseFloatArgs(x, vals...)
+	if err == nil && len(nums) == 0 {
+		err = ErrEmpty
+	}
+	if err != nil {
+		return 0, funcError("Div", err, append([]interface{}{x}, vals...)...)
+	}
+
+	q := nums[0]
+	for _, v := range nums[1:] {
+		if v == 0 {
+			return 0, funcError("Div", ErrDivisionByZero, append([]interface{}{x}, vals...)...)
+		}
+		q /= v
+	}
+	return q, nil
+}
+
+func foldFloats(name string, op func(a, b float64) float64, x interface{}, vals []interface{}) (float64, error) {
+	nums, err := parseFloatArgs(x, vals...)
+	if err == nil && len(nums) == 0 {
+		err = ErrEmpty
+	}
+	if err != nil {
+		return 0, funcError(name, err, append([]interface{}{x}, vals...)...)
+	}
+
+	r := nums[0]
+	for _, v := range nums[1:] {
+		r = op(r, v)
+	}
+	return r, nil
+}
+
+// floatArg converts a single argument of the adapters to a float64.
+func floatArg(v interface{}) (float64, error) {
+	n, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.checkedFloat64()
+}
+
+// Round rounds a number to the provided number of decimal places. The number
+// is the last argument so that it can be piped, and it may be preceded by a
+// rounding mode, either "half-up" (the default), "half-even" or
+// "toward-zero". Rounding is performed on the decimal representation of the
+// number, so 2.675 rounds to 2.68 even though the nearest float64 is slightly
+// smaller. A negative number of places rounds to a power of ten.
+//		{{ Round 2 2.675 }} -> 2.68
+//		{{ .Price | Round 2 "half-even" }}
+//		{{ Round -3 123456 }} -> 123000
+func Round(places int, args ...interface{}) (float64, error) {
+	d, err := roundArgs(places, args)
+	if err != nil {
+		return 0, funcError("Round", err, append([]interface{}{places}, args...)...)
+	}
+	return d.Float64(), nil
+}
+
+// Truncate removes the digits of a number after the provided number of
+// decimal places. It is the same as Round with the "toward-zero" mode.
+func Truncate(places int, x interface{}) (float64, error) {
+	d, err := roundArgs(places, []interface{}{RoundTowardZero, x})
+	if err != nil {
+		return 0, funcError("Truncate", err, places, x)
+	}
+	return d.Float64(), nil
+}
+
+// RoundSig rounds a number to the provided number of significant figures. The
+// arguments after the number of figures are the same as for Round.
+//		{{ RoundSig 3 123456 }} -> 123000
+//		{{ RoundSig 2 0.012345 }} -> 0.012
+func RoundSig(figures int, args ...interface{}) (float64, error) {
+	d, err := roundSig(figures, args)
+	if err != nil {
+		return 0, funcError("RoundSig", err, append([]interface{}{figures}, args...)...)
+	}
+	return d.Float64(), nil
+}
+
+func roundSig(figures int, args []interface{}) (Decimal, error) {
+	if figures < 1 {
+		return Decimal{}, fmt.Errorf("number of significant figures must be positive, got %d", figures)
+	}
+
+	d, mode, err := decRoundArgs(args)
+	if err != nil || d.Sign() == 0 {
+		return d, err
+	}
+
+	// The number of digits before the decimal point, which is negative for
+	// numbers with leading zeros after the decimal point.
+	digits := len(new(big.Int).Abs(d.int()).String()) - d.scale
+	return d.Round(figures-digits, mode)
+}
+
+// FormatFixed rounds a number like Round and formats it with exactly the
+// provided number of decimal places. It replaces printf "%.2f", which rounds
+// the binary value of a float64 instead of its decimal representation.
+//		{{ FormatFixed 2 2.675 }} -> 2.68
+//		{{ FormatFixed 2 3 }} -> 3.00
+func FormatFixed(places int, args ...interface{}) (string, error) {
+	d, err := roundArgs(places, args)
+	if err != nil {
+		return "", funcError("FormatFixed", err, append([]interface{}{places}, args...)...)
+	}
+	return d.String(), nil
+}
+
+// roundArgs parses the optional rounding mode and the number of the rounding
+// functions and rounds the number.
+func roundArgs(places int, args []interface{}) (Decimal, error) {
+	d, mode, err := decRoundArgs(args)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return d.Round(places, mode)
 }
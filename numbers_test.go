@@ -1,6 +1,11 @@
 package temple
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"testing"
 )
@@ -61,6 +66,112 @@ func Test_parseIntArgs(t *testing.T) {
 	}
 }
 
+func TestMax(t *testing.T) {
+	type item struct {
+		Qty uint32
+	}
+	type qty int16
+
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    interface{}
+		wantMin interface{}
+		wantErr bool
+	}{
+		{name: "ints", args: []interface{}{1, 5, 3}, want: 5, wantMin: 1},
+		{name: "int64 slice", args: []interface{}{[]int64{4, -2, 9}}, want: 9, wantMin: -2},
+		{name: "uint32", args: []interface{}{uint32(7), uint8(2)}, want: 7, wantMin: 2},
+		{name: "float32 slice", args: []interface{}{[]float32{1.5, 0.5}}, want: 1.5, wantMin: 0.5},
+		{name: "json.Number", args: []interface{}{[]interface{}{json.Number("3"), json.Number("10")}}, want: 10, wantMin: 3},
+		{name: "typed slice", args: []interface{}{[]qty{3, 8, 1}}, want: 8, wantMin: 1},
+		{name: "array", args: []interface{}{[3]uint{3, 8, 1}}, want: 8, wantMin: 1},
+		{name: "mixed first int", args: []interface{}{NewList(1, 2.5, 2)}, want: 2.5, wantMin: 1.0},
+		{name: "mixed first float", args: []interface{}{0.5, 2}, want: 2.0, wantMin: 0.5},
+		{name: "Set", args: []interface{}{NewSet(4, 2, 6)}, want: 6, wantMin: 2},
+		{name: "numeric strings", args: []interface{}{NewList("4", "12")}, want: 12, wantMin: 4},
+		{name: "large int64", args: []interface{}{int64(1<<62 + 1), int64(1 << 62)}, want: int(1<<62 + 1), wantMin: int(1 << 62)},
+		{name: "big.Int", args: []interface{}{big.NewInt(3), []*big.Int{big.NewInt(-1)}}, want: 3, wantMin: -1},
+		{name: "nil big.Int", args: []interface{}{(*big.Int)(nil)}, wantErr: true},
+		{name: "empty", args: []interface{}{[]int{}}, wantErr: true},
+		{name: "invalid", args: []interface{}{item{Qty: 1}}, wantErr: true},
+		{name: "non numeric string", args: []interface{}{NewList("a")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Max(tt.args[0], tt.args[1:]...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Max() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+				t.Errorf("Max() = %#v, want %#v", got, tt.want)
+			}
+
+			got, err = Min(tt.args[0], tt.args[1:]...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Min() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.wantMin) && !tt.wantErr {
+				t.Errorf("Min() = %#v, want %#v", got, tt.wantMin)
+			}
+		})
+	}
+}
+
+func TestBigArithmetic(t *testing.T) {
+	a, _ := new(big.Int).SetString("123456789012345678901234567891", 10)
+	b, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	tests := []struct {
+		name    string
+		fn      func() (interface{}, error)
+		want    string
+		wantErr error
+	}{
+		{name: "Max", fn: func() (interface{}, error) { return Max(a, b) }, want: "123456789012345678901234567891"},
+		{name: "Min", fn: func() (interface{}, error) { return Min([]*big.Int{a, b}) }, want: "123456789012345678901234567890"},
+		{name: "Max json.Number", fn: func() (interface{}, error) { return Max(json.Number(b.String()), json.Number(a.String())) }, want: "123456789012345678901234567891"},
+		{name: "Max large big.Int", fn: func() (interface{}, error) { return Max(new(big.Int).Lsh(big.NewInt(1), 70), 1) }, want: "1180591620717411303424"},
+		{name: "Min large big.Int", fn: func() (interface{}, error) { return Min(new(big.Int).Lsh(big.NewInt(1), 70), 1) }, want: "1"},
+		{name: "Max big.Float and big.Rat", fn: func() (interface{}, error) { return Max(big.NewFloat(1.5), big.NewRat(1, 4)) }, want: "1.5"},
+		{name: "Min big.Float and big.Rat", fn: func() (interface{}, error) { return Min(big.NewFloat(1.5), big.NewRat(1, 4)) }, want: "0.25"},
+		{name: "Larger", fn: func() (interface{}, error) { return Larger(b, a) }, want: "123456789012345678901234567891"},
+		{name: "Smaller", fn: func() (interface{}, error) { return Smaller(a, b) }, want: "123456789012345678901234567890"},
+		{name: "Clamp", fn: func() (interface{}, error) { return Clamp(0, b, a) }, want: "123456789012345678901234567890"},
+		{name: "Sign", fn: func() (interface{}, error) { return Sign(new(big.Int).Neg(a)) }, want: "-1"},
+		{name: "SumOf", fn: func() (interface{}, error) { return SumOf(a, b) }, want: "246913578024691357802469135781"},
+		{name: "SumOf big.Rat", fn: func() (interface{}, error) { return SumOf(big.NewRat(1, 10), 0.2) }, want: "0.3"},
+		{name: "Mean", fn: func() (interface{}, error) { return Mean(a, b) }, want: "123456789012345678901234567890.5"},
+		{name: "Mode", fn: func() (interface{}, error) { return Mode(a, b, a) }, want: "123456789012345678901234567891"},
+		{name: "Max uint64", fn: func() (interface{}, error) { return Max(uint64(math.MaxUint64), uint64(math.MaxUint64-1)) }, want: "18446744073709551615"},
+		{name: "Min uint64", fn: func() (interface{}, error) { return Min(uint64(math.MaxUint64), uint64(math.MaxUint64-1)) }, want: "18446744073709551614"},
+		{name: "IntSum", fn: func() (interface{}, error) { return IntSum(a, 1) }, wantErr: ErrOverflow},
+		{name: "IntMax float", fn: func() (interface{}, error) { return IntMax(1e300) }, wantErr: ErrOverflow},
+		{name: "IntMin float", fn: func() (interface{}, error) { return IntMin(-1e19, 1) }, wantErr: ErrOverflow},
+		{name: "IntMax", fn: func() (interface{}, error) { return IntMax(a) }, wantErr: ErrOverflow},
+		{name: "FloatMax", fn: func() (interface{}, error) { return FloatMax(a) }, wantErr: ErrOverflow},
+		{name: "Median", fn: func() (interface{}, error) { return Median(a, b) }, wantErr: ErrOverflow},
+		{name: "Sqrt", fn: func() (interface{}, error) { return Sqrt(a) }, wantErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if s := fmt.Sprint(got); s != tt.want {
+				t.Errorf("got %s (%T), want %s", s, got, tt.want)
+			}
+		})
+	}
+}
+
 var vals = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 
 var res interface{}
@@ -80,3 +191,44 @@ func BenchmarkIntMax(b *testing.B) {
 	}
 	res = m
 }
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() (interface{}, error)
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "Round half-up", fn: func() (interface{}, error) { return Round(2, 2.675) }, want: 2.68},
+		{name: "Round half-up negative", fn: func() (interface{}, error) { return Round(1, -0.25) }, want: -0.3},
+		{name: "Round half-even", fn: func() (interface{}, error) { return Round(0, RoundHalfEven, 2.5) }, want: 2.0},
+		{name: "Round toward-zero", fn: func() (interface{}, error) { return Round(1, RoundTowardZero, -1.99) }, want: -1.9},
+		{name: "Round negative places", fn: func() (interface{}, error) { return Round(-3, 123456) }, want: 123000.0},
+		{name: "Round json.Number", fn: func() (interface{}, error) { return Round(1, json.Number("1.25")) }, want: 1.3},
+		{name: "Round unknown mode", fn: func() (interface{}, error) { return Round(1, "up", 1.25) }, wantErr: true},
+		{name: "Round not numeric", fn: func() (interface{}, error) { return Round(1, "abc") }, wantErr: true},
+		{name: "Truncate", fn: func() (interface{}, error) { return Truncate(2, 2.679) }, want: 2.67},
+		{name: "Truncate negative", fn: func() (interface{}, error) { return Truncate(0, -2.9) }, want: -2.0},
+		{name: "RoundSig large", fn: func() (interface{}, error) { return RoundSig(3, 123456) }, want: 123000.0},
+		{name: "RoundSig small", fn: func() (interface{}, error) { return RoundSig(2, 0.012345) }, want: 0.012},
+		{name: "RoundSig carry", fn: func() (interface{}, error) { return RoundSig(2, 9.96) }, want: 10.0},
+		{name: "RoundSig zero", fn: func() (interface{}, error) { return RoundSig(2, 0) }, want: 0.0},
+		{name: "RoundSig mode", fn: func() (interface{}, error) { return RoundSig(1, RoundHalfEven, 25) }, want: 20.0},
+		{name: "RoundSig no figures", fn: func() (interface{}, error) { return RoundSig(0, 25) }, wantErr: true},
+		{name: "FormatFixed", fn: func() (interface{}, error) { return FormatFixed(2, 2.675) }, want: "2.68"},
+		{name: "FormatFixed pad", fn: func() (interface{}, error) { return FormatFixed(2, 3) }, want: "3.00"},
+		{name: "FormatFixed mode", fn: func() (interface{}, error) { return FormatFixed(1, RoundTowardZero, "9.99") }, want: "9.9"},
+		{name: "FormatFixed no places", fn: func() (interface{}, error) { return FormatFixed(0, 0.5) }, want: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
 	htemplate "html/template"
@@ -9,71 +9,209 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	ttemplate "text/template"
+	"text/template/parse"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/pkg/schema"
 )
 
 // App represents the temple application. To populate the struct with values
 // from the command line as described in the documentation, instantiate the
 // struct with the New() function.
 type App struct {
+	// Command is the subcommand being run. An empty Command renders the
+	// templates.
+	Command string
+
 	Templates  []string
 	DataFile   string
+	SchemaFile string
 	OutputFile string
+	FileRoot   string
+
+	// Numbers determines the Go type of the numbers in the data file.
+	Numbers temple.NumberMode
 
 	HTMLFuncMap temple.FuncMap
 	TextFuncMap temple.FuncMap
 
 	HTML  bool
 	Watch bool
+	// Diff indicates that the output should be compared to the existing
+	// output file instead of being written. Only the single output file is
+	// compared, as there is no directory or manifest render mode.
+	Diff bool
+	// Update indicates that the test command should rewrite golden files.
+	Update bool
+	// Format is the output format of the funcs command: text, markdown or
+	// json.
+	Format string
+
+	// LintDisabled contains the IDs of the lint rules to suppress.
+	LintDisabled []string
+
+	// Limits bounds the resources used while rendering the templates.
+	Limits temple.Limits
+
+	// Allow and Deny restrict the functions available to the templates. See
+	// temple.FuncFilter for the format of the entries.
+	Allow []string
+	Deny  []string
+
+	// MergePolicy determines how the With*FuncMap methods handle functions
+	// that are already defined.
+	MergePolicy temple.MergePolicy
+	// MergeAllowed contains the names of functions that may be overridden
+	// regardless of MergePolicy.
+	MergeAllowed []string
 
 	logger *logger
+
+	// merges counts the calls to the With*FuncMap methods, and the origins
+	// record which call added each function to the FuncMaps.
+	merges      int
+	htmlOrigins map[string][]funcOrigin
+	textOrigins map[string][]funcOrigin
+	// mergeErr is the first conflict found with the error merge policy.
+	mergeErr error
+
+	watcherMu sync.Mutex
+	watcher   *fsnotify.Watcher
+	watched   map[string]bool
+}
+
+// commands maps the available subcommands to their descriptions.
+var commands = map[string]string{
+	"funcs": "list the functions available to the templates",
+	"lint":  "report common mistakes in the templates",
+	"test":  "run the golden file tests in the provided directories",
 }
 
 func usage() {
 	fmt.Fprint(os.Stderr, "Name:\n\ttemple - compile Go templates from the command line\n\n")
-	fmt.Fprint(os.Stderr, "Usage:\n\ttemple [OPTION]... <BASE TEMPLATE> [TEMPLATE]...\n\n")
-	fmt.Fprintf(os.Stderr, "Options:\n")
+	fmt.Fprint(os.Stderr, "Usage:\n\ttemple [OPTION]... <BASE TEMPLATE> [TEMPLATE]...\n")
+	fmt.Fprint(os.Stderr, "\ttemple lint [OPTION]... <BASE TEMPLATE> [TEMPLATE]...\n")
+	fmt.Fprint(os.Stderr, "\ttemple test [OPTION]... <DIRECTORY>...\n")
+	fmt.Fprint(os.Stderr, "\ttemple funcs [OPTION]...\n\n")
+	fmt.Fprint(os.Stderr, "Commands:\n")
+	for _, c := range sortedKeys(commands) {
+		fmt.Fprintf(os.Stderr, "  %-8s%s\n", c, commands[c])
+	}
+	fmt.Fprint(os.Stderr, "\nOptions:\n")
 	flag.PrintDefaults()
 }
 
 // New creates a new App. The default values are populated with values from the
-// command line. The list of command line args will be used as the list of
-// template files. At least one must be provided. The available command line
-// flags are:
+// command line. If the first command line arg is the name of a command, then
+// it is used as the App's Command. The remaining list of command line args
+// will be used as the list of template files. At least one must be provided.
+// The available command line flags are:
 //	 -o string: The output filename
 //	 -d string: The data file
+//	 -schema string: A JSON Schema used to validate the data
+//	 -numbers string: How numbers in the data are decoded: float64, number, int64 or big
+//	 -root string: The directory that file functions are restricted to
 //	 -w: 		Indicates that the input files should be watched for changes
 //	 -v: 		Indicates that additional logging should be displayed
 //	 -html:		Indicates that the html/template parser should be used
+//	 -diff:		Indicates that a diff against the output file should be printed
+//	 -disable string: A comma separated list of lint rules to suppress
+//	 -update:	Indicates that the test command should update golden files
+//	 -format string: The output format of the funcs command
+//	 -allow string: A comma separated list of the functions templates may use
+//	 -deny string: A comma separated list of the functions templates may not use
+//	 -max-output int: The maximum number of bytes of output
+//	 -max-range int: The maximum number of range iterations
+//	 -max-depth int: The maximum depth of nested templates
+//	 -timeout duration: The maximum duration of rendering
+//	 -merge string: The policy used when merging FuncMaps: last, first, error or warn
+//	 -override string: A comma separated list of functions that may be overridden
 func New() *App {
 	flag.Usage = usage
 	flagHTML := flag.Bool("html", false, "use html/template for template parsing")
 	flagWatch := flag.Bool("w", false, "watch input files for changes")
+	flagDiff := flag.Bool("diff", false, "print a diff against the output file instead of writing it")
 	flagVerbose := flag.Bool("v", false, "show extra log info")
 	flagData := flag.String("d", "", "a JSON file containing the template data")
+	flagNumbers := flag.String("numbers", "float64", "how numbers in the data file are decoded: float64, number (json.Number), int64 or big")
+	flagSchema := flag.String("schema", "", "a JSON Schema file used to validate the template data")
 	flagOutput := flag.String("o", "", "the output filename")
-	flag.Parse()
+	flagRoot := flag.String("root", ".", "the directory that file functions are restricted to")
+	flagDisable := flag.String("disable", "", "a comma separated list of lint rules to suppress")
+	flagUpdate := flag.Bool("update", false, "rewrite golden files when running tests")
+	flagFormat := flag.String("format", "text", "the output format of the funcs command: text, markdown or json")
+	flagAllow := flag.String("allow", "", "a comma separated list of the functions templates may use, as names, category:NAME or cap:NAME")
+	flagDeny := flag.String("deny", "", "a comma separated list of the functions templates may not use, as names, category:NAME or cap:NAME")
+	flagMaxOutput := flag.Int64("max-output", 0, "the maximum number of bytes of output, or 0 for no limit")
+	flagMaxRange := flag.Int("max-range", 0, "the maximum number of range iterations, or 0 for no limit")
+	flagMaxDepth := flag.Int("max-depth", 0, "the maximum depth of nested templates, or 0 for no limit")
+	flagTimeout := flag.Duration("timeout", 0, "the maximum duration of rendering, or 0 for no limit")
+	flagMerge := flag.String("merge", "last", "the policy used when merging FuncMaps: last, first, error or warn")
+	flagOverride := flag.String("override", "", "a comma separated list of functions that may be overridden when merging FuncMaps")
+
+	args := os.Args[1:]
+	var command string
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			command, args = args[0], args[1:]
+		}
+	}
+	// The default FlagSet exits on error, so the error can be ignored.
+	_ = flag.CommandLine.Parse(args)
+
+	logger := newLogger(*flagVerbose)
+	policy, err := temple.ParseMergePolicy(*flagMerge)
+	if err != nil {
+		logger.Fatal("%v", err)
+	}
+	numbers, err := temple.ParseNumberMode(*flagNumbers)
+	if err != nil {
+		logger.Fatal("%v", err)
+	}
 
 	return &App{
-		Templates:   flag.Args(),
-		DataFile:    *flagData,
-		OutputFile:  *flagOutput,
-		TextFuncMap: make(temple.FuncMap),
-		HTMLFuncMap: make(temple.FuncMap),
-		Watch:       *flagWatch,
-		HTML:        *flagHTML,
-		logger:      newLogger(*flagVerbose),
+		Command:      command,
+		LintDisabled: splitList(*flagDisable),
+		Templates:    flag.Args(),
+		DataFile:     *flagData,
+		Numbers:      numbers,
+		SchemaFile:   *flagSchema,
+		OutputFile:   *flagOutput,
+		FileRoot:     *flagRoot,
+		TextFuncMap:  make(temple.FuncMap),
+		HTMLFuncMap:  make(temple.FuncMap),
+		Watch:        *flagWatch,
+		HTML:         *flagHTML,
+		Diff:         *flagDiff,
+		Update:       *flagUpdate,
+		Format:       *flagFormat,
+		Limits: temple.Limits{
+			MaxOutputBytes:     *flagMaxOutput,
+			MaxRangeIterations: *flagMaxRange,
+			MaxDepth:           *flagMaxDepth,
+			Timeout:            *flagTimeout,
+		},
+		Allow:        splitList(*flagAllow),
+		Deny:         splitList(*flagDeny),
+		MergePolicy:  policy,
+		MergeAllowed: splitList(*flagOverride),
+		logger:       logger,
 	}
 }
 
 // WithFuncMap merges the provided FuncMap into the App's text and HTML FuncMaps.
 // This method can be called multiple times to merge in multiple FuncMaps.
+// Functions that are already defined are handled according to the App's
+// MergePolicy.
 func (a *App) WithFuncMap(f temple.FuncMap) *App {
-	a.HTMLFuncMap = temple.MergeFuncMaps(a.HTMLFuncMap, f)
-	a.TextFuncMap = temple.MergeFuncMaps(a.TextFuncMap, f)
+	a.merges++
+	warned := a.mergeFuncMap(&a.HTMLFuncMap, &a.htmlOrigins, f, nil)
+	a.mergeFuncMap(&a.TextFuncMap, &a.textOrigins, f, warned)
 	return a
 }
 
@@ -81,32 +219,83 @@ func (a *App) WithFuncMap(f temple.FuncMap) *App {
 func (a *App) ClearFuncMaps() *App {
 	a.HTMLFuncMap.Clear()
 	a.TextFuncMap.Clear()
+	a.htmlOrigins, a.textOrigins = nil, nil
 	return a
 }
 
 // WithHTMLFuncMap merges the provided FuncMap into the App's HTML FuncMap.
 // This method can me called multipled times to merge in multiple FuncMaps.
 func (a *App) WithHTMLFuncMap(f temple.FuncMap) *App {
-	a.HTMLFuncMap = temple.MergeFuncMaps(a.HTMLFuncMap, f)
+	a.merges++
+	a.mergeFuncMap(&a.HTMLFuncMap, &a.htmlOrigins, f, nil)
 	return a
 }
 
 // ClearHTMLFuncMap resets the App's HTML FuncMap.
 func (a *App) ClearHTMLFuncMap() *App {
 	a.HTMLFuncMap.Clear()
+	a.htmlOrigins = nil
 	return a
 }
 
 // WithTextFuncMap merges the provided FuncMap into the App's text FuncMap.
 // This method can me called multipled times to merge in multiple FuncMaps.
 func (a *App) WithTextFuncMap(f temple.FuncMap) *App {
-	a.TextFuncMap = temple.MergeFuncMaps(a.TextFuncMap, f)
+	a.merges++
+	a.mergeFuncMap(&a.TextFuncMap, &a.textOrigins, f, nil)
 	return a
 }
 
+// mergeFuncMap merges f into dst according to the App's MergePolicy. With the
+// warn policy, every override whose name is not in warned is logged. The names
+// of the logged functions are returned so that merging the same FuncMap into
+// both FuncMaps only warns once.
+func (a *App) mergeFuncMap(dst *temple.FuncMap, origins *map[string][]funcOrigin, f temple.FuncMap, warned map[string]bool) map[string]bool {
+	m := temple.Merger{
+		Policy: a.MergePolicy,
+		Allow:  a.MergeAllowed,
+		Warnf:  func(string, ...interface{}) {},
+	}
+
+	out, overrides, err := m.Merge(*dst, f)
+	if err != nil {
+		if a.mergeErr == nil {
+			a.mergeErr = fmt.Errorf("%v (merge %d)", err, a.merges)
+		}
+		return warned
+	}
+
+	if warned == nil {
+		warned = make(map[string]bool)
+		for _, name := range a.MergeAllowed {
+			warned[name] = true
+		}
+	}
+	if a.MergePolicy == temple.MergeWarn {
+		for _, o := range overrides {
+			if warned[o.Name] {
+				continue
+			}
+			warned[o.Name] = true
+
+			prev := (*origins)[o.Name]
+			if i := activeOrigin(prev, (*dst)[o.Name]); i >= 0 {
+				a.logger.Warn("temple: %s from %s overridden by merge %d", o.Name, prev[i], a.merges)
+			} else {
+				a.logger.Warn("temple: %s overridden by merge %d", o.Name, a.merges)
+			}
+		}
+	}
+
+	*dst = out
+	recordOrigins(origins, f, a.merges)
+	return warned
+}
+
 // ClearTextFuncMap resets the App's text FuncMap.
 func (a *App) ClearTextFuncMap() *App {
 	a.TextFuncMap.Clear()
+	a.textOrigins = nil
 	return a
 }
 
@@ -114,10 +303,25 @@ func (a *App) ClearTextFuncMap() *App {
 // method will never return.
 func (a *App) Run() error {
 
-	if flag.NArg() == 0 {
+	if a.Command == "funcs" {
+		return a.funcs()
+	}
+
+	if a.mergeErr != nil {
+		return a.mergeErr
+	}
+
+	if len(a.Templates) == 0 {
 		a.logger.Fatal("temple: at least one input file required")
 	}
 
+	switch a.Command {
+	case "lint":
+		return a.lint()
+	case "test":
+		return a.test()
+	}
+
 	var f parseFunc
 	if a.HTML {
 		f = a.parseHTML
@@ -125,12 +329,16 @@ func (a *App) Run() error {
 		f = a.parseText
 	}
 
+	if a.Diff {
+		return a.diff(f)
+	}
+
 	if a.Watch {
 		a.watch(f)
 		return nil
 	}
 
-	data, err := readDataFile(a.DataFile)
+	data, err := a.loadData()
 	if err != nil {
 		return err
 	}
@@ -141,7 +349,7 @@ func (a *App) Run() error {
 	}
 	defer w.Close()
 
-	err = f(flag.Args(), data, w)
+	err = f(a.Templates, data, w)
 	if err != nil {
 		return err
 	}
@@ -158,9 +366,14 @@ func (a *App) watch(parse parseFunc) {
 	}
 	defer watcher.Close()
 
+	a.watcherMu.Lock()
+	a.watcher = watcher
+	a.watched = make(map[string]bool)
+	a.watcherMu.Unlock()
+
 	go func() {
 		var err error
-		data, err := readDataFile(a.DataFile)
+		data, err := a.loadData()
 		if err != nil {
 			a.logger.Fatal("error reading data file: %v", err)
 		}
@@ -182,7 +395,7 @@ func (a *App) watch(parse parseFunc) {
 				}
 
 				if event.Name == a.DataFile {
-					data, err = readDataFile(a.DataFile)
+					data, err = a.loadData()
 					if err != nil {
 						a.logger.Error("error reading data file: %v", err)
 						continue
@@ -206,16 +419,14 @@ func (a *App) watch(parse parseFunc) {
 	}()
 
 	for _, f := range a.Templates {
-		a.logger.Info("Watching %s for changes...\n", f)
-		err = watcher.Add(f)
+		err = a.watchFile(f)
 		if err != nil {
 			a.logger.Fatal(err.Error())
 		}
 	}
 
 	if a.DataFile != "" {
-		a.logger.Info("Watching %s for changes...\n", a.DataFile)
-		err = watcher.Add(a.DataFile)
+		err = a.watchFile(a.DataFile)
 		if err != nil {
 			a.logger.Fatal(err.Error())
 		}
@@ -224,6 +435,39 @@ func (a *App) watch(parse parseFunc) {
 	<-(make(chan struct{}))
 }
 
+// watchFile adds the file to the watch list if the App is watching files and
+// the file is not already being watched.
+func (a *App) watchFile(filename string) error {
+	a.watcherMu.Lock()
+	defer a.watcherMu.Unlock()
+
+	if a.watcher == nil || a.watched[filename] {
+		return nil
+	}
+
+	a.logger.Status("Watching %s for changes...\n", filename)
+	err := a.watcher.Add(filename)
+	if err != nil {
+		return err
+	}
+
+	a.watched[filename] = true
+	return nil
+}
+
+// fileFuncs returns the file functions restricted to the App's FileRoot. When
+// watching, every file read or listed by these functions is added to the watch
+// list.
+func (a *App) fileFuncs() temple.FuncMap {
+	fs := temple.NewFileSystem(a.FileRoot)
+	fs.OnRead = func(path string) {
+		if err := a.watchFile(path); err != nil {
+			a.logger.Error("error watching %s: %v", path, err)
+		}
+	}
+	return fs.FuncMap()
+}
+
 func (a *App) update(parse parseFunc, data interface{}) error {
 	var err error
 	var f *os.File
@@ -250,7 +494,32 @@ func (a *App) update(parse parseFunc, data interface{}) error {
 	return err
 }
 
-func readDataFile(filename string) (interface{}, error) {
+// loadData reads the App's data file. If a schema is provided, the schema's
+// defaults are applied to the data, and the data is validated against it.
+func (a *App) loadData() (interface{}, error) {
+	data, err := readDataFile(a.DataFile, a.Numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.SchemaFile == "" {
+		return data, nil
+	}
+
+	s, err := schema.Load(a.SchemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data = s.ApplyDefaults(data)
+	if err := s.Validate(data); err != nil {
+		return nil, fmt.Errorf("temple: data does not match schema:\n%v", err)
+	}
+
+	return data, nil
+}
+
+func readDataFile(filename string, mode temple.NumberMode) (interface{}, error) {
 	if filename == "" {
 		return nil, nil
 	}
@@ -260,13 +529,27 @@ func readDataFile(filename string) (interface{}, error) {
 		return nil, err
 	}
 
-	var data interface{}
-	err = json.Unmarshal(f, &data)
-	if err != nil {
-		return nil, err
+	return temple.DecodeJSON(f, mode)
+}
+
+// splitList splits a comma separated list, ignoring empty elements.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
 	}
+	return out
+}
 
-	return data, nil
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func getWriter(filename string) (io.WriteCloser, error) {
@@ -285,12 +568,34 @@ func getWriter(filename string) (io.WriteCloser, error) {
 func (a *App) parseHTML(infiles []string, data interface{}, w io.Writer) error {
 	_, name := filepath.Split(infiles[0])
 
-	t, err := htemplate.New(name).Funcs(a.HTMLFuncMap.HTML()).ParseFiles(infiles...)
+	// Tpl only uses its FuncMap when called, so the map can be filled in
+	// after the functions are restricted.
+	t := htemplate.New(name)
+	funcs := make(temple.FuncMap)
+	restricted, denied, err := a.restrict(temple.MergeFuncMaps(
+		temple.HTMLIncludeFuncs(t, funcs),
+		a.fileFuncs(),
+		a.HTMLFuncMap,
+	))
 	if err != nil {
 		return err
 	}
+	temple.MergeFuncMaps(funcs, restricted)
 
-	err = t.Execute(w, data)
+	t, err = t.Funcs(funcs.HTML()).ParseFiles(infiles...)
+	if err != nil {
+		return err
+	}
+
+	var trees []*parse.Tree
+	for _, tt := range t.Templates() {
+		trees = append(trees, tt.Tree)
+	}
+	if err := checkRestricted(trees, denied); err != nil {
+		return err
+	}
+
+	err = a.Limits.ExecuteHTML(context.Background(), t, w, data)
 	if err != nil {
 		return err
 	}
@@ -301,12 +606,30 @@ func (a *App) parseHTML(infiles []string, data interface{}, w io.Writer) error {
 func (a *App) parseText(infiles []string, data interface{}, w io.Writer) error {
 	_, name := filepath.Split(infiles[0])
 
-	t, err := ttemplate.New(name).Funcs(a.TextFuncMap.Text()).ParseFiles(infiles...)
+	t := ttemplate.New(name)
+	funcs, denied, err := a.restrict(temple.MergeFuncMaps(
+		temple.TextIncludeFuncs(t),
+		a.fileFuncs(),
+		a.TextFuncMap,
+	))
 	if err != nil {
 		return err
 	}
 
-	err = t.Execute(w, data)
+	t, err = t.Funcs(funcs.Text()).ParseFiles(infiles...)
+	if err != nil {
+		return err
+	}
+
+	var trees []*parse.Tree
+	for _, tt := range t.Templates() {
+		trees = append(trees, tt.Tree)
+	}
+	if err := checkRestricted(trees, denied); err != nil {
+		return err
+	}
+
+	err = a.Limits.ExecuteText(context.Background(), t, w, data)
 	if err != nil {
 		return err
 	}
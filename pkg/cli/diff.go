@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattmeyers/temple/pkg/diff"
+)
+
+// diff renders the templates to memory and prints a unified diff against the
+// current contents of the App's output file. An error is returned if the
+// output file would change.
+func (a *App) diff(parse parseFunc) error {
+	if a.OutputFile == "" {
+		return errors.New("temple: -diff requires an output file")
+	}
+
+	data, err := a.loadData()
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := parse(a.Templates, data, &b); err != nil {
+		return err
+	}
+
+	oldName := diffLabel("a", a.OutputFile)
+	old, err := ioutil.ReadFile(a.OutputFile)
+	if os.IsNotExist(err) {
+		oldName = "/dev/null"
+	} else if err != nil {
+		return err
+	}
+
+	d := diff.Unified(oldName, diffLabel("b", a.OutputFile), string(old), b.String())
+	if d == "" {
+		return nil
+	}
+
+	fmt.Fprint(os.Stdout, d)
+	if oldName == "/dev/null" {
+		return fmt.Errorf("temple: %s would be added", a.OutputFile)
+	}
+	return fmt.Errorf("temple: %s is out of date", a.OutputFile)
+}
+
+// diffLabel names a file in a diff header. As with git, relative paths are
+// prefixed to distinguish the old and new versions.
+func diffLabel(prefix, filename string) string {
+	if filepath.IsAbs(filename) {
+		return filepath.ToSlash(filename)
+	}
+	return prefix + "/" + filepath.ToSlash(filename)
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	ttemplate "text/template"
+
+	"github.com/mattmeyers/temple"
+)
+
+// funcOrigin records where a function in one of the App's FuncMaps came from.
+type funcOrigin struct {
+	Package string `json:"package"`
+	// Merge is the 1-based index of the With*FuncMap call that added the
+	// function. Functions provided by the CLI itself have a Merge of 0.
+	Merge int `json:"merge"`
+
+	fn uintptr
+}
+
+func (o funcOrigin) String() string {
+	if o.Merge == 0 {
+		return o.Package + " (provided by the CLI)"
+	}
+	return fmt.Sprintf("%s (merge %d)", o.Package, o.Merge)
+}
+
+// recordOrigins appends the origin of every function in f to origins.
+func recordOrigins(origins *map[string][]funcOrigin, f temple.FuncMap, merge int) {
+	if *origins == nil {
+		*origins = make(map[string][]funcOrigin)
+	}
+
+	for name, fn := range f {
+		(*origins)[name] = append((*origins)[name], funcOrigin{Package: funcPackage(fn), Merge: merge, fn: funcPointer(fn)})
+	}
+}
+
+// activeOrigin returns the index of the last origin in o that added fn, or -1
+// if there is none.
+func activeOrigin(o []funcOrigin, fn interface{}) int {
+	p := funcPointer(fn)
+	for i := len(o) - 1; i >= 0; i-- {
+		if o[i].fn == p {
+			return i
+		}
+	}
+	return -1
+}
+
+func funcPointer(fn interface{}) uintptr {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return 0
+	}
+	return v.Pointer()
+}
+
+// funcPackage returns the import path of the package that defines fn.
+func funcPackage(fn interface{}) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return ""
+	}
+
+	rf := runtime.FuncForPC(v.Pointer())
+	if rf == nil {
+		return ""
+	}
+
+	// Function names have the form path/to/pkg.Func, path/to/pkg.Type.Method
+	// or path/to/pkg.Func.func1, so the package ends at the first dot after
+	// the final slash.
+	name := rf.Name()
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+type funcInfo struct {
+	Name        string       `json:"name"`
+	Signature   string       `json:"signature"`
+	Package     string       `json:"package"`
+	Merge       int          `json:"merge"`
+	Category    string       `json:"category,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Overrides   []funcOrigin `json:"overrides,omitempty"`
+	Ignored     []funcOrigin `json:"ignored,omitempty"`
+}
+
+// funcs prints every function available to the templates along with its
+// signature and origin. Functions that replaced others while merging list the
+// origins of the replaced functions, and functions that were kept by the
+// first merge policy list the origins of the ignored functions.
+func (a *App) funcs() error {
+	funcs, origins := a.TextFuncMap, a.textOrigins
+	if a.HTML {
+		funcs, origins = a.HTMLFuncMap, a.htmlOrigins
+	}
+
+	// The functions provided by the CLI are added before the App's
+	// FuncMaps, so they can be overridden.
+	cli := temple.MergeFuncMaps(temple.TextIncludeFuncs(ttemplate.New("")), a.fileFuncs())
+	all := make(map[string][]funcOrigin)
+	recordOrigins(&all, cli, 0)
+	for name, o := range origins {
+		all[name] = append(all[name], o...)
+	}
+	funcs = temple.MergeFuncMaps(cli, funcs)
+
+	infos := make([]funcInfo, 0, len(funcs))
+	for name, fn := range funcs {
+		o := all[name]
+		info := funcInfo{
+			Name:      name,
+			Signature: name + strings.TrimPrefix(reflect.TypeOf(fn).String(), "func"),
+			Package:   funcPackage(fn),
+		}
+		if i := activeOrigin(o, fn); i >= 0 {
+			info.Merge = o[i].Merge
+			for j, other := range o {
+				switch {
+				case other.fn == o[i].fn:
+				case j < i:
+					info.Overrides = append(info.Overrides, other)
+				default:
+					info.Ignored = append(info.Ignored, other)
+				}
+			}
+		}
+		if f, ok := temple.DefaultRegistry.LookupFunc(name, fn); ok {
+			info.Category = f.Category
+			info.Description = f.Description
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	switch a.Format {
+	case "", "text":
+		return printFuncsText(infos)
+	case "markdown", "md":
+		return printFuncsMarkdown(infos)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	}
+	return fmt.Errorf("temple: unknown format %q", a.Format)
+}
+
+func printFuncsText(infos []funcInfo) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, f := range infos {
+		fmt.Fprintf(w, "%s\t%s\n", f.Signature, f.Package)
+		for _, o := range f.Overrides {
+			fmt.Fprintf(w, "  overrides %s\t\n", o)
+		}
+		for _, o := range f.Ignored {
+			fmt.Fprintf(w, "  ignored %s\t\n", o)
+		}
+	}
+	return w.Flush()
+}
+
+func printFuncsMarkdown(infos []funcInfo) error {
+	var b strings.Builder
+	b.WriteString("| Function | Signature | Package | Description | Overrides | Ignored |\n")
+	b.WriteString("| -------- | --------- | ------- | ----------- | --------- | ------- |\n")
+	for _, f := range infos {
+		fmt.Fprintf(&b, "| `%s` | `%s` | `%s` | %s | %s | %s |\n",
+			f.Name, f.Signature, f.Package, markdownEscape(f.Description),
+			markdownEscape(joinOrigins(f.Overrides)), markdownEscape(joinOrigins(f.Ignored)))
+	}
+
+	_, err := fmt.Fprint(os.Stdout, b.String())
+	return err
+}
+
+func joinOrigins(o []funcOrigin) string {
+	s := make([]string, len(o))
+	for i := range o {
+		s[i] = o[i].String()
+	}
+	return strings.Join(s, ", ")
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
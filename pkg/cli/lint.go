@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	ttemplate "text/template"
+
+	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/pkg/lint"
+	"github.com/mattmeyers/temple/pkg/schema"
+)
+
+// lint reports common mistakes in the App's templates. An error is returned if
+// any problems are found.
+func (a *App) lint() error {
+	funcs := a.TextFuncMap
+	if a.HTML {
+		funcs = a.HTMLFuncMap
+	}
+
+	// Include and Tpl are only bound to a template set for their names and
+	// signatures, so an empty set is sufficient.
+	funcs = temple.MergeFuncMaps(
+		temple.TextIncludeFuncs(ttemplate.New("")),
+		a.fileFuncs(),
+		funcs,
+	)
+
+	cfg := lint.Config{
+		Funcs:    funcs,
+		Disabled: a.LintDisabled,
+		Numbers:  a.Numbers,
+	}
+
+	if a.SchemaFile != "" {
+		s, err := schema.Load(a.SchemaFile)
+		if err != nil {
+			return err
+		}
+		cfg.Schema = s
+	}
+
+	findings, err := lint.Files(a.Templates, cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range findings {
+		a.logger.Info("%s", f)
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("temple: %d problem(s) found", len(findings))
+	}
+	return nil
+}
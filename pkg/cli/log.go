@@ -7,16 +7,29 @@ import (
 
 type logger struct {
 	logger  *log.Logger
+	status  *log.Logger
 	verbose bool
 }
 
 func newLogger(verbose bool) *logger {
 	return &logger{
 		logger:  log.New(os.Stdout, "", 0),
+		status:  log.New(os.Stderr, "", 0),
 		verbose: verbose,
 	}
 }
 
+// Status logs progress messages to stderr, so that they do not mix with the
+// rendered templates when those are written to stdout.
+func (l *logger) Status(format string, v ...interface{}) {
+	l.status.Printf(format, v...)
+}
+
+// Warn logs warnings to stderr, like Status.
+func (l *logger) Warn(format string, v ...interface{}) {
+	l.status.Printf(format, v...)
+}
+
 func (l *logger) Info(format string, v ...interface{}) {
 	l.logger.Printf(format, v...)
 }
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/pkg/lint"
+)
+
+// restrict applies the App's Allow and Deny lists to funcs. Removed functions
+// are replaced with stubs that fail when called, so that templates referencing
+// them still parse and can be reported by checkRestricted. Functions denied by
+// name are stubbed even if funcs does not contain them, which allows builtin
+// functions such as call to be denied.
+func (a *App) restrict(funcs temple.FuncMap) (temple.FuncMap, map[string]bool, error) {
+	if len(a.Allow) == 0 && len(a.Deny) == 0 {
+		return funcs, nil, nil
+	}
+
+	out, removed, err := temple.FilterFuncMap(funcs, a.Allow, a.Deny)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, entry := range a.Deny {
+		if !strings.Contains(entry, ":") {
+			removed = append(removed, entry)
+		}
+	}
+
+	denied := make(map[string]bool)
+	for _, name := range removed {
+		denied[name] = true
+		out[name] = deniedFunc(name)
+	}
+	return out, denied, nil
+}
+
+func deniedFunc(name string) func(...interface{}) (string, error) {
+	return func(...interface{}) (string, error) {
+		return "", fmt.Errorf("function %s is not allowed", name)
+	}
+}
+
+// checkRestricted returns an error describing every reference to a denied
+// function in the trees.
+func checkRestricted(trees []*parse.Tree, denied map[string]bool) error {
+	if len(denied) == 0 {
+		return nil
+	}
+
+	sort.Slice(trees, func(i, j int) bool { return trees[i].ParseName+trees[i].Name < trees[j].ParseName+trees[j].Name })
+
+	var problems []string
+	for _, tree := range trees {
+		if tree == nil || tree.Root == nil {
+			continue
+		}
+
+		lint.Inspect(tree.Root, func(n parse.Node) bool {
+			if id, ok := n.(*parse.IdentifierNode); ok && denied[id.Ident] {
+				loc, _ := tree.ErrorContext(n)
+				problems = append(problems, fmt.Sprintf("%s: function %s is not allowed", loc, id.Ident))
+			}
+			return true
+		})
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("temple: template uses denied functions:\n%s", strings.Join(problems, "\n"))
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/templatetest"
+)
+
+// test runs the golden file tests found in each of the App's Templates, which
+// are treated as directories. An error is returned if any test fails.
+func (a *App) test() error {
+	funcs := a.TextFuncMap
+	if a.HTML {
+		funcs = a.HTMLFuncMap
+	}
+
+	// Denied functions are replaced with stubs that fail the tests using them.
+	funcs, _, err := a.restrict(temple.MergeFuncMaps(a.fileFuncs(), funcs))
+	if err != nil {
+		return err
+	}
+
+	opts := templatetest.Options{
+		Funcs:   funcs,
+		HTML:    a.HTML,
+		Numbers: a.Numbers,
+		Limits:  a.Limits,
+		Update:  a.Update,
+	}
+
+	var failed int
+	for _, dir := range a.Templates {
+		results, err := templatetest.Run(dir, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range results {
+			switch {
+			case r.Err != nil:
+				failed++
+				a.logger.Error("FAIL %s: %v", r.Case.Name, r.Err)
+			case r.Diff != "":
+				failed++
+				a.logger.Error("FAIL %s\n%s", r.Case.Name, r.Diff)
+			case r.Updated:
+				a.logger.Info("updated %s", r.Case.Golden)
+			default:
+				a.logger.Debug("ok %s", r.Case.Name)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("temple: %d test(s) failed", failed)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// Package diff produces line based unified diffs.
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Context is the number of unchanged lines shown around each change.
+const Context = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type edit struct {
+	kind opKind
+	line string
+	// a and b are the 0-based line numbers of the edit in the old and new
+	// texts respectively.
+	a, b int
+}
+
+// Unified returns a unified diff transforming oldText into newText. The names
+// are used in the diff's header. If the texts are equal, the empty string is
+// returned.
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	edits := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	for _, h := range hunks(edits) {
+		aStart, aLen, bStart, bLen := 0, 0, 0, 0
+		first := true
+		for _, e := range h {
+			if first {
+				aStart, bStart = e.a+1, e.b+1
+				first = false
+			}
+			if e.kind != opInsert {
+				aLen++
+			}
+			if e.kind != opDelete {
+				bLen++
+			}
+		}
+		// An empty range refers to the line before the hunk.
+		if aLen == 0 {
+			aStart--
+		}
+		if bLen == 0 {
+			bStart--
+		}
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, e := range h {
+			prefix := " "
+			switch e.kind {
+			case opDelete:
+				prefix = "-"
+			case opInsert:
+				prefix = "+"
+			}
+
+			b.WriteString(prefix)
+			b.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func hunkRange(start, n int) string {
+	if n == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, n)
+}
+
+// splitLines splits s into lines, keeping the line endings.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// hunks groups the edits into hunks containing the changed lines and up to
+// Context unchanged lines on either side. Changes separated by at most
+// 2*Context unchanged lines share a hunk, since their context lines would
+// otherwise touch or overlap.
+func hunks(edits []edit) [][]edit {
+	var changes []int
+	for i, e := range edits {
+		if e.kind != opEqual {
+			changes = append(changes, i)
+		}
+	}
+
+	var out [][]edit
+	for i := 0; i < len(changes); {
+		start := changes[i] - Context
+		if start < 0 {
+			start = 0
+		}
+
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*Context+1 {
+			j++
+		}
+
+		end := changes[j] + Context + 1
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		out = append(out, edits[start:end])
+		i = j + 1
+	}
+	return out
+}
+
+// diffLines computes the shortest edit script transforming a into b using the
+// linear space variant of Myers' algorithm. Within each run of changed lines,
+// the deleted lines come before the inserted ones.
+func diffLines(a, b []string) []edit {
+	d := &differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+
+	edits := d.edits
+	for i := 0; i < len(edits); {
+		if edits[i].kind == opEqual {
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(edits) && edits[j].kind != opEqual {
+			j++
+		}
+
+		run := edits[i:j]
+		x, y := run[0].a, run[0].b
+		sort.SliceStable(run, func(p, q int) bool { return run[p].kind < run[q].kind })
+		for k := range run {
+			run[k].a, run[k].b = x, y
+			if run[k].kind == opDelete {
+				x++
+			} else {
+				y++
+			}
+		}
+		i = j
+	}
+	return edits
+}
+
+// differ accumulates the edits transforming a into b.
+type differ struct {
+	a, b  []string
+	edits []edit
+}
+
+func (d *differ) add(kind opKind, x, y int) {
+	line := ""
+	if kind == opInsert {
+		line = d.b[y]
+	} else {
+		line = d.a[x]
+	}
+	d.edits = append(d.edits, edit{kind: kind, line: line, a: x, b: y})
+}
+
+// compare adds the edits transforming a[aLo:aHi] into b[bLo:bHi]. The
+// problem is split at the middle snake of a shortest edit script, so only
+// linear space is needed.
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.add(opEqual, aLo, bLo)
+		aLo++
+		bLo++
+	}
+
+	suffix := 0
+	for aHi-suffix > aLo && bHi-suffix > bLo && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			d.add(opInsert, aLo, y)
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			d.add(opDelete, x, bLo)
+		}
+	default:
+		xs, ys, xe, ye := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, xs, bLo, ys)
+		for x, y := xs, ys; x < xe; x, y = x+1, y+1 {
+			d.add(opEqual, x, y)
+		}
+		d.compare(xe, aHi, ye, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.add(opEqual, aHi+i, bHi+i)
+	}
+}
+
+// middleSnake returns the start and end of the snake in the middle of a
+// shortest edit script transforming a[aLo:aHi] into b[bLo:bHi]. The search
+// runs forward from the start and backward from the end until the two paths
+// overlap. The texts must not be empty, and must not share a prefix or suffix,
+// so that the edit scripts on either side of the snake are shorter than the
+// whole script.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (xs, ys, xe, ye int) {
+	a, b := d.a[aLo:aHi], d.b[bLo:bHi]
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+
+	// vf holds the furthest x reached on each diagonal k = x - y by the
+	// forward search, and vb the furthest distance from the end reached on
+	// each diagonal c = u - w by the backward search, where u = n - x and
+	// w = m - y. Diagonal k corresponds to diagonal delta - k.
+	max := (n + m + 1) / 2
+	off := max + 1
+	vf := make([]int, 2*max+3)
+	vb := make([]int, 2*max+3)
+
+	for D := 0; D <= max; D++ {
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && vf[off+k-1] < vf[off+k+1]) {
+				x = vf[off+k+1]
+			} else {
+				x = vf[off+k-1] + 1
+			}
+
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+
+			if c := delta - k; odd && c >= -(D-1) && c <= D-1 && x+vb[off+c] >= n {
+				return aLo + sx, bLo + sy, aLo + x, bLo + y
+			}
+		}
+
+		for c := -D; c <= D; c += 2 {
+			var u int
+			if c == -D || (c != D && vb[off+c-1] < vb[off+c+1]) {
+				u = vb[off+c+1]
+			} else {
+				u = vb[off+c-1] + 1
+			}
+
+			w := u - c
+			su, sw := u, w
+			for u < n && w < m && a[n-u-1] == b[m-w-1] {
+				u++
+				w++
+			}
+			vb[off+c] = u
+
+			if k := delta - c; !odd && k >= -D && k <= D && u+vf[off+k] >= n {
+				return aLo + n - u, bLo + m - w, aLo + n - su, bLo + m - sw
+			}
+		}
+	}
+
+	// The paths always overlap by the time D reaches max.
+	panic("diff: middle snake not found")
+}
@@ -0,0 +1,60 @@
+package lint
+
+import "text/template/parse"
+
+// Inspect traverses the parse tree rooted at node in depth-first order. It
+// starts by calling fn(node). If fn returns true, Inspect is invoked
+// recursively for each of the children of node.
+func Inspect(node parse.Node, fn func(parse.Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			Inspect(c, fn)
+		}
+	case *parse.ActionNode:
+		Inspect(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Decl {
+			Inspect(v, fn)
+		}
+		for _, c := range n.Cmds {
+			Inspect(c, fn)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			Inspect(a, fn)
+		}
+	case *parse.ChainNode:
+		Inspect(n.Node, fn)
+	case *parse.IfNode:
+		inspectBranch(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		inspectBranch(&n.BranchNode, fn)
+	case *parse.WithNode:
+		inspectBranch(&n.BranchNode, fn)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			Inspect(n.Pipe, fn)
+		}
+	}
+}
+
+func inspectBranch(n *parse.BranchNode, fn func(parse.Node) bool) {
+	Inspect(n.Pipe, fn)
+	if n.List != nil {
+		Inspect(n.List, fn)
+	}
+	if n.ElseList != nil {
+		Inspect(n.ElseList, fn)
+	}
+}
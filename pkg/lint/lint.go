@@ -0,0 +1,554 @@
+// Package lint reports common mistakes in Go templates. Unlike parse errors,
+// these mistakes do not prevent a template from executing but usually produce
+// incorrect output or indicate dead code.
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/pkg/schema"
+)
+
+// Rule IDs identify the check that produced a Finding. Any rule can be
+// suppressed by adding its ID to Config.Disabled.
+const (
+	// RuleUnusedTemplate reports templates that are defined but never
+	// executed with the template action or the Include function.
+	RuleUnusedTemplate = "unused-template"
+	// RuleUndefinedTemplate reports template actions and Include calls that
+	// reference a template that is never defined.
+	RuleUndefinedTemplate = "undefined-template"
+	// RuleFuncArity reports calls to functions in the FuncMap with the wrong
+	// number of arguments. Piped values count as the final argument.
+	RuleFuncArity = "func-arity"
+	// RuleUnusedVariable reports variables that are declared but never used.
+	RuleUnusedVariable = "unused-variable"
+	// RuleTrailingWhitespace reports whitespace that ends up at the end of an
+	// output line because a trim marker was not used.
+	RuleTrailingWhitespace = "trailing-whitespace"
+	// RuleUnknownField reports field accesses that are not described by the
+	// data schema. This rule only runs when a schema is provided.
+	RuleUnknownField = "unknown-field"
+	// RuleFuncArgType reports function arguments whose types can't be passed
+	// to the function. This rule only runs when a schema is provided.
+	RuleFuncArgType = "func-arg-type"
+)
+
+// Rules lists the IDs of all rules in the order they are run.
+var Rules = []string{
+	RuleUnusedTemplate,
+	RuleUndefinedTemplate,
+	RuleFuncArity,
+	RuleUnusedVariable,
+	RuleTrailingWhitespace,
+	RuleUnknownField,
+	RuleFuncArgType,
+}
+
+// A Finding is a single problem reported by a rule.
+type Finding struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", f.File, f.Line, f.Col, f.Message, f.Rule)
+}
+
+// Config controls how templates are linted.
+type Config struct {
+	// Funcs contains the functions available to the templates. Any function
+	// not in Funcs or the text/template builtins causes a parse error.
+	Funcs temple.FuncMap
+	// Disabled contains the IDs of the rules that should not be reported.
+	Disabled []string
+	// Schema, if not nil, describes the data passed to the base template. It
+	// enables the type checking rules.
+	Schema *schema.Schema
+	// Numbers is the mode used to decode the numbers in the data, which
+	// determines their Go types when the type checking rules are applied.
+	Numbers temple.NumberMode
+}
+
+// builtins contains the names of the functions predefined by text/template.
+// Only the names are needed for parsing, but the parser ignores nil values, so
+// the values are non-nil placeholders.
+var builtins = map[string]interface{}{
+	"and": true, "call": true, "html": true, "index": true, "slice": true,
+	"js": true, "len": true, "not": true, "or": true, "print": true,
+	"printf": true, "println": true, "urlquery": true, "eq": true, "ge": true,
+	"gt": true, "le": true, "lt": true, "ne": true,
+}
+
+// Files parses and lints the provided template files. The files are treated as
+// a single template set, as with template.ParseFiles, where the first file is
+// the template that gets executed. Findings are sorted by location.
+func Files(filenames []string, cfg Config) ([]Finding, error) {
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("lint: no files named")
+	}
+
+	l := &linter{
+		cfg:      cfg,
+		base:     filepath.Base(filenames[0]),
+		disabled: make(map[string]bool),
+		reported: make(map[Finding]bool),
+		texts:    make(map[string]string),
+		files:    make(map[string]bool),
+		trees:    make(map[string]*parse.Tree),
+	}
+	for _, r := range cfg.Disabled {
+		l.disabled[r] = true
+	}
+
+	for _, filename := range filenames {
+		b, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := l.parse(filename, string(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	l.run()
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		a, b := l.findings[i], l.findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+
+	return l.findings, nil
+}
+
+type linter struct {
+	cfg      Config
+	disabled map[string]bool
+	reported map[Finding]bool
+
+	// base is the name of the template that gets executed.
+	base string
+
+	// texts maps file names to their contents.
+	texts map[string]string
+	// files contains the names of the templates created for each file.
+	files map[string]bool
+	// trees maps template names to their parse trees. As with ParseFiles,
+	// later definitions replace earlier ones.
+	trees map[string]*parse.Tree
+	// names contains the template names in sorted order.
+	names []string
+
+	findings []Finding
+}
+
+func (l *linter) parse(filename, text string) error {
+	name := filepath.Base(filename)
+	l.texts[filename] = text
+	l.files[name] = true
+
+	trees, err := parse.Parse(filename, text, "", "", l.cfg.Funcs, builtins)
+	if err != nil {
+		return err
+	}
+
+	for n, t := range trees {
+		// parse.Parse names the top level template after the provided name,
+		// but ParseFiles names it after the base name of the file.
+		if n == filename {
+			n = name
+		}
+
+		if _, ok := l.trees[n]; !ok {
+			l.names = append(l.names, n)
+		}
+		l.trees[n] = t
+	}
+	sort.Strings(l.names)
+
+	return nil
+}
+
+func (l *linter) report(rule string, t *parse.Tree, pos parse.Pos, format string, args ...interface{}) {
+	if l.disabled[rule] {
+		return
+	}
+
+	line, col := position(l.texts[t.ParseName], int(pos))
+	f := Finding{
+		Rule:    rule,
+		File:    t.ParseName,
+		Line:    line,
+		Col:     col,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	// A template can be checked multiple times with different types for
+	// its dot, so the same problem may be found more than once.
+	if l.reported[f] {
+		return
+	}
+	l.reported[f] = true
+	l.findings = append(l.findings, f)
+}
+
+// position converts a byte offset into a 1-based line and column.
+func position(text string, pos int) (line, col int) {
+	if pos > len(text) {
+		pos = len(text)
+	}
+
+	before := text[:pos]
+	line = 1 + strings.Count(before, "\n")
+	col = pos - strings.LastIndex(before, "\n")
+	return line, col
+}
+
+func (l *linter) run() {
+	l.checkTemplates()
+	l.checkTypes()
+
+	for _, name := range l.names {
+		t := l.trees[name]
+		l.checkArity(t)
+		l.checkVariables(t)
+		l.checkWhitespace(t, t.Root)
+	}
+}
+
+// checkTemplates reports templates that are defined but never used, and
+// references to templates that are never defined.
+func (l *linter) checkTemplates() {
+	used := make(map[string]bool)
+
+	for _, name := range l.names {
+		t := l.trees[name]
+		Inspect(t.Root, func(n parse.Node) bool {
+			var ref string
+			var pos parse.Pos
+			switch n := n.(type) {
+			case *parse.TemplateNode:
+				ref, pos = n.Name, n.Pos
+			case *parse.CommandNode:
+				// Include "name" is equivalent to the template action.
+				if len(n.Args) < 2 || !isIdentifier(n.Args[0], "Include") {
+					return true
+				}
+				s, ok := n.Args[1].(*parse.StringNode)
+				if !ok {
+					return true
+				}
+				ref, pos = s.Text, s.Pos
+			default:
+				return true
+			}
+
+			used[ref] = true
+			if _, ok := l.trees[ref]; !ok {
+				l.report(RuleUndefinedTemplate, t, pos, "template %q is not defined", ref)
+			}
+			return true
+		})
+	}
+
+	for _, name := range l.names {
+		if l.files[name] || used[name] {
+			continue
+		}
+
+		t := l.trees[name]
+		l.report(RuleUnusedTemplate, t, t.Root.Pos, "template %q is defined but never used", name)
+	}
+}
+
+func isIdentifier(n parse.Node, name string) bool {
+	id, ok := n.(*parse.IdentifierNode)
+	return ok && id.Ident == name
+}
+
+// checkArity reports calls to functions in the FuncMap that receive the wrong
+// number of arguments.
+func (l *linter) checkArity(t *parse.Tree) {
+	Inspect(t.Root, func(n parse.Node) bool {
+		pipe, ok := n.(*parse.PipeNode)
+		if !ok {
+			return true
+		}
+
+		for i, cmd := range pipe.Cmds {
+			for j, arg := range cmd.Args {
+				id, ok := arg.(*parse.IdentifierNode)
+				if !ok {
+					continue
+				}
+
+				// An identifier in the first position is called with the
+				// remaining arguments plus the piped value. In any other
+				// position it is called without arguments.
+				got := 0
+				if j == 0 {
+					got = len(cmd.Args) - 1
+					if i > 0 {
+						got++
+					}
+				}
+
+				l.checkCall(t, id, got)
+			}
+		}
+		return true
+	})
+}
+
+func (l *linter) checkCall(t *parse.Tree, id *parse.IdentifierNode, got int) {
+	fn, ok := l.cfg.Funcs[id.Ident]
+	if !ok || fn == nil {
+		return
+	}
+
+	typ := reflect.TypeOf(fn)
+	if typ.Kind() != reflect.Func {
+		return
+	}
+
+	want := typ.NumIn()
+	if typ.IsVariadic() {
+		if got < want-1 {
+			l.report(RuleFuncArity, t, id.Pos, "%s expects at least %d arguments, got %d", id.Ident, want-1, got)
+		}
+	} else if got != want {
+		l.report(RuleFuncArity, t, id.Pos, "%s expects %d arguments, got %d", id.Ident, want, got)
+	}
+}
+
+type variable struct {
+	name string
+	pos  parse.Pos
+	used bool
+	// ignore suppresses reporting the variable even if it is unused.
+	ignore bool
+}
+
+// scopes is a stack of variable scopes. A new scope is pushed for every
+// control structure, mirroring the scoping rules of text/template.
+type scopes struct {
+	stack [][]*variable
+}
+
+func (s *scopes) push() { s.stack = append(s.stack, nil) }
+
+func (s *scopes) pop() []*variable {
+	vars := s.stack[len(s.stack)-1]
+	s.stack = s.stack[:len(s.stack)-1]
+	return vars
+}
+
+func (s *scopes) declare(v *variable) {
+	s.stack[len(s.stack)-1] = append(s.stack[len(s.stack)-1], v)
+}
+
+func (s *scopes) lookup(name string) *variable {
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		for j := len(s.stack[i]) - 1; j >= 0; j-- {
+			if s.stack[i][j].name == name {
+				return s.stack[i][j]
+			}
+		}
+	}
+	return nil
+}
+
+// checkVariables reports variables that are declared but never used.
+func (l *linter) checkVariables(t *parse.Tree) {
+	s := &scopes{}
+	s.push()
+	l.walkVariables(t, s, t.Root)
+	l.reportUnused(t, s.pop())
+}
+
+func (l *linter) reportUnused(t *parse.Tree, vars []*variable) {
+	for _, v := range vars {
+		if !v.used && !v.ignore && v.name != "$_" {
+			l.report(RuleUnusedVariable, t, v.pos, "variable %s is declared but never used", v.name)
+		}
+	}
+}
+
+func (l *linter) walkVariables(t *parse.Tree, s *scopes, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			l.walkVariables(t, s, c)
+		}
+	case *parse.ActionNode:
+		l.walkPipe(t, s, n.Pipe, false)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			l.walkPipe(t, s, n.Pipe, false)
+		}
+	case *parse.IfNode:
+		l.walkBranch(t, s, &n.BranchNode, false)
+	case *parse.WithNode:
+		l.walkBranch(t, s, &n.BranchNode, false)
+	case *parse.RangeNode:
+		l.walkBranch(t, s, &n.BranchNode, true)
+	}
+}
+
+func (l *linter) walkBranch(t *parse.Tree, s *scopes, n *parse.BranchNode, isRange bool) {
+	s.push()
+	l.walkPipe(t, s, n.Pipe, isRange)
+	l.walkVariables(t, s, n.List)
+	l.walkVariables(t, s, n.ElseList)
+	l.reportUnused(t, s.pop())
+}
+
+func (l *linter) walkPipe(t *parse.Tree, s *scopes, pipe *parse.PipeNode, isRange bool) {
+	if pipe == nil {
+		return
+	}
+
+	// The commands are evaluated before the variables are declared, so
+	// {{ $x := $x }} refers to an outer $x.
+	for _, cmd := range pipe.Cmds {
+		Inspect(cmd, func(n parse.Node) bool {
+			switch n := n.(type) {
+			case *parse.VariableNode:
+				if v := s.lookup(n.Ident[0]); v != nil {
+					v.used = true
+				}
+			case *parse.PipeNode:
+				l.walkPipe(t, s, n, false)
+				return false
+			}
+			return true
+		})
+	}
+
+	if pipe.IsAssign {
+		return
+	}
+
+	for i, d := range pipe.Decl {
+		s.declare(&variable{
+			name: d.Ident[0],
+			pos:  d.Pos,
+			// The index in {{ range $i, $e := ... }} must be declared to
+			// access the element, so it is not required to be used.
+			ignore: isRange && i == 0 && len(pipe.Decl) == 2,
+		})
+	}
+}
+
+// checkWhitespace reports whitespace that is output at the end of a line
+// because the surrounding actions do not trim it.
+func (l *linter) checkWhitespace(t *parse.Tree, list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+
+	for i, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			// Whitespace following an action on the same line.
+			if i > 0 {
+				if ws := leadingBlanks(n.Text); ws > 0 && ws < len(n.Text) && n.Text[ws] == '\n' {
+					l.report(RuleTrailingWhitespace, t, n.Pos, "trailing whitespace after action; use -}} to trim it")
+				}
+			}
+		case *parse.IfNode:
+			l.checkBranchWhitespace(t, list.Nodes, i, &n.BranchNode)
+		case *parse.RangeNode:
+			l.checkBranchWhitespace(t, list.Nodes, i, &n.BranchNode)
+		case *parse.WithNode:
+			l.checkBranchWhitespace(t, list.Nodes, i, &n.BranchNode)
+		}
+	}
+}
+
+// checkBranchWhitespace reports indentation before the opening action of a
+// control structure, and before its end action, when the action is alone on
+// its line. Since these actions produce no output, the indentation is output
+// as a line containing only whitespace.
+func (l *linter) checkBranchWhitespace(t *parse.Tree, nodes []parse.Node, i int, n *parse.BranchNode) {
+	if i > 0 && startsLine(n.List) {
+		if prev, ok := nodes[i-1].(*parse.TextNode); ok {
+			if ws, ok := indentation(prev.Text); ok {
+				l.report(RuleTrailingWhitespace, t, prev.Pos+parse.Pos(len(prev.Text)-ws), "whitespace before action is output on its own line; use {{- to trim it")
+			}
+		}
+	}
+
+	last := n.List
+	if n.ElseList != nil {
+		last = n.ElseList
+	}
+	if last != nil && len(last.Nodes) > 0 && i+1 < len(nodes) {
+		prev, ok := last.Nodes[len(last.Nodes)-1].(*parse.TextNode)
+		next, nok := nodes[i+1].(*parse.TextNode)
+		if ok && nok && startsWithNewline(next.Text) {
+			if ws, ok := indentation(prev.Text); ok {
+				l.report(RuleTrailingWhitespace, t, prev.Pos+parse.Pos(len(prev.Text)-ws), "whitespace before {{end}} is output on its own line; use {{- to trim it")
+			}
+		}
+	}
+
+	l.checkWhitespace(t, n.List)
+	l.checkWhitespace(t, n.ElseList)
+}
+
+// startsLine determines if the list begins with a newline, meaning the action
+// that opened it ends its line.
+func startsLine(list *parse.ListNode) bool {
+	if list == nil || len(list.Nodes) == 0 {
+		return false
+	}
+	n, ok := list.Nodes[0].(*parse.TextNode)
+	return ok && startsWithNewline(n.Text)
+}
+
+func startsWithNewline(b []byte) bool {
+	ws := leadingBlanks(b)
+	return ws < len(b) && b[ws] == '\n'
+}
+
+// indentation returns the length of the final line of b if that line is not
+// empty and consists only of spaces and tabs.
+func indentation(b []byte) (int, bool) {
+	i := strings.LastIndexByte(string(b), '\n')
+	if i < 0 {
+		return 0, false
+	}
+
+	tail := b[i+1:]
+	return len(tail), len(tail) > 0 && leadingBlanks(tail) == len(tail)
+}
+
+func leadingBlanks(b []byte) int {
+	for i, c := range b {
+		if c != ' ' && c != '\t' {
+			return i
+		}
+	}
+	return len(b)
+}
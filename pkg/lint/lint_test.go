@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/pkg/schema"
+)
+
+func TestFiles(t *testing.T) {
+	files := []string{"testdata/base.tmpl", "testdata/defines.tmpl"}
+	funcs := temple.FuncMap{
+		"Join":    temple.Join,
+		"Include": func(string, interface{}) (string, error) { return "", nil },
+	}
+
+	tests := []struct {
+		name     string
+		disabled []string
+		want     []string
+	}{
+		{
+			name: "All rules",
+			want: []string{
+				"testdata/base.tmpl:1:4: variable $unused is declared but never used (unused-variable)",
+				"testdata/base.tmpl:3:1: whitespace before action is output on its own line; use {{- to trim it (trailing-whitespace)",
+				"testdata/base.tmpl:4:17: Join expects 2 arguments, got 1 (func-arity)",
+				"testdata/base.tmpl:5:1: whitespace before {{end}} is output on its own line; use {{- to trim it (trailing-whitespace)",
+				"testdata/base.tmpl:7:13: template \"missing\" is not defined (undefined-template)",
+				"testdata/defines.tmpl:2:20: template \"dead\" is defined but never used (unused-template)",
+			},
+		},
+		{
+			name:     "Disabled rules",
+			disabled: []string{RuleTrailingWhitespace, RuleUnusedVariable, RuleFuncArity},
+			want: []string{
+				"testdata/base.tmpl:7:13: template \"missing\" is not defined (undefined-template)",
+				"testdata/defines.tmpl:2:20: template \"dead\" is defined but never used (unused-template)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := Files(files, Config{Funcs: funcs, Disabled: tt.disabled})
+			if err != nil {
+				t.Fatalf("Files() error = %v", err)
+			}
+
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Files() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFiles_schema(t *testing.T) {
+	s, err := schema.Load("testdata/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funcs := temple.FuncMap{
+		"FormatMask": temple.FormatMask,
+		"Commas":     temple.Commas,
+		"ToUpper":    temple.ToUpper,
+		"Div":        temple.Div,
+	}
+
+	findings, err := Files([]string{"testdata/typed.tmpl"}, Config{Funcs: funcs, Schema: s})
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+
+	// Commas accepts numbers of any kind, so passing it a number on line 6 is
+	// not reported.
+	want := []string{
+		"testdata/typed.tmpl:1:13: unknown field Nmae in .Customer (unknown-field)",
+		"testdata/typed.tmpl:2:32: argument 2 of FormatMask has type float64, want string (func-arg-type)",
+		"testdata/typed.tmpl:4:42: unknown field .Prce (unknown-field)",
+		"testdata/typed.tmpl:5:55: unknown field .Foo (unknown-field)",
+		"testdata/typed.tmpl:8:30: unknown field .Bad (unknown-field)",
+		"testdata/typed.tmpl:9:41: argument 1 of ToUpper has type float64, want string (func-arg-type)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Files() = %q, want %q", got, want)
+	}
+}
+
+func TestFiles_schemaNumbers(t *testing.T) {
+	s, err := schema.Load("testdata/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funcs := temple.FuncMap{
+		"FormatMask": temple.FormatMask,
+		"Commas":     temple.Commas,
+		"ToUpper":    temple.ToUpper,
+		"Div":        temple.Div,
+	}
+
+	tests := []struct {
+		numbers temple.NumberMode
+		want    []string
+	}{
+		{
+			numbers: temple.NumbersJSON,
+			want: []string{
+				"testdata/typed.tmpl:2:32: argument 2 of FormatMask has type json.Number, want string (func-arg-type)",
+				"testdata/typed.tmpl:4:31: argument 2 of Div has type json.Number, want float64 (func-arg-type)",
+				"testdata/typed.tmpl:9:41: argument 1 of ToUpper has type json.Number, want string (func-arg-type)",
+			},
+		},
+		{
+			// Numbers may be decoded as int64 or float64, so only integers
+			// are checked.
+			numbers: temple.NumbersInt64,
+			want: []string{
+				"testdata/typed.tmpl:2:32: argument 2 of FormatMask has type int64, want string (func-arg-type)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.numbers.String(), func(t *testing.T) {
+			findings, err := Files([]string{"testdata/typed.tmpl"}, Config{Funcs: funcs, Schema: s, Numbers: tt.numbers, Disabled: []string{RuleUnknownField}})
+			if err != nil {
+				t.Fatalf("Files() error = %v", err)
+			}
+
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Files() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
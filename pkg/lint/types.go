@@ -0,0 +1,545 @@
+package lint
+
+import (
+	"encoding/json"
+	"reflect"
+	"text/template/parse"
+
+	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/pkg/schema"
+)
+
+var (
+	boolType    = reflect.TypeOf(false)
+	intType     = reflect.TypeOf(0)
+	int64Type   = reflect.TypeOf(int64(0))
+	float64Type = reflect.TypeOf(float64(0))
+	numberType  = reflect.TypeOf(json.Number(""))
+	stringType  = reflect.TypeOf("")
+	mapType     = reflect.TypeOf(map[string]interface{}(nil))
+	sliceType   = reflect.TypeOf([]interface{}(nil))
+)
+
+// typ is the static type of a value in a template. Values described by the
+// data schema have a schema, values returned by functions have a Go type, and
+// literal values have the node they were parsed from. The zero typ is an
+// unknown type, for which no checks are performed.
+type typ struct {
+	schema *schema.Schema
+	goType reflect.Type
+	lit    parse.Node
+	// numbers is the mode used to decode the numbers described by schema.
+	numbers temple.NumberMode
+}
+
+func (t typ) known() bool {
+	return t.schema != nil || t.goType != nil || t.lit != nil
+}
+
+// child returns the type of a value described by s that is part of a value of
+// type t.
+func (t typ) child(s *schema.Schema) typ {
+	return typ{schema: s, numbers: t.numbers}
+}
+
+// runtime returns the Go type of the value when the template is executed. The
+// data described by a schema is assumed to be decoded by temple.DecodeJSON
+// with the mode in t.numbers.
+func (t typ) runtime() reflect.Type {
+	switch {
+	case t.goType != nil:
+		return t.goType
+	case t.schema != nil:
+		return runtimeType(t.schema, t.numbers)
+	}
+	return nil
+}
+
+func runtimeType(s *schema.Schema, numbers temple.NumberMode) reflect.Type {
+	s = s.Resolve()
+	if s.IsTrue() || s.IsFalse() {
+		return nil
+	}
+
+	var types []string
+	for _, t := range s.Type {
+		// A null value can't be checked statically.
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+
+	if len(types) == 0 {
+		switch {
+		case len(s.Properties) > 0:
+			return mapType
+		case s.Items != nil:
+			return sliceType
+		}
+		return nil
+	} else if len(types) > 1 {
+		return nil
+	}
+
+	switch types[0] {
+	case "string":
+		return stringType
+	case "number", "integer":
+		return numberRuntimeType(types[0], numbers)
+	case "boolean":
+		return boolType
+	case "object":
+		return mapType
+	case "array":
+		return sliceType
+	}
+	return nil
+}
+
+// numberRuntimeType returns the Go type of a number of the provided schema
+// type. In the int64 and big modes, whole numbers are decoded as integers, so
+// the type of a value of the number type is unknown.
+func numberRuntimeType(name string, numbers temple.NumberMode) reflect.Type {
+	switch numbers {
+	case temple.NumbersJSON:
+		return numberType
+	case temple.NumbersInt64, temple.NumbersBig:
+		if name == "integer" {
+			return int64Type
+		}
+		return nil
+	}
+	return float64Type
+}
+
+func (t typ) String() string {
+	if t.lit != nil {
+		return t.lit.String()
+	}
+	if rt := t.runtime(); rt != nil {
+		return rt.String()
+	}
+	return "unknown"
+}
+
+// checker performs the type checking rules. Templates are checked starting
+// with the base template, whose dot is described by the data schema. Each
+// template called by a template action is checked with the type of the value
+// passed to it.
+type checker struct {
+	l       *linter
+	tree    *parse.Tree
+	vars    []map[string]typ
+	visited map[visit]bool
+	checked map[string]bool
+}
+
+type visit struct {
+	name   string
+	schema *schema.Schema
+	goType reflect.Type
+}
+
+// checkTypes reports field accesses that are not described by the schema and
+// function arguments whose types do not match the function's signature.
+func (l *linter) checkTypes() {
+	if l.cfg.Schema == nil {
+		return
+	}
+
+	c := &checker{
+		l:       l,
+		visited: make(map[visit]bool),
+		checked: make(map[string]bool),
+	}
+
+	c.template(l.base, typ{schema: l.cfg.Schema, numbers: l.cfg.Numbers})
+
+	// Templates that are never called are still checked for mismatched
+	// literal arguments and function results.
+	for _, name := range l.names {
+		if !c.checked[name] {
+			c.template(name, typ{})
+		}
+	}
+}
+
+func (c *checker) template(name string, dot typ) {
+	t, ok := c.l.trees[name]
+	if !ok {
+		return
+	}
+
+	v := visit{name: name, schema: dot.schema, goType: dot.runtime()}
+	if c.visited[v] {
+		return
+	}
+	c.visited[v] = true
+	c.checked[name] = true
+
+	tree, vars := c.tree, c.vars
+	c.tree, c.vars = t, []map[string]typ{{"$": dot}}
+	c.walk(t.Root, dot)
+	c.tree, c.vars = tree, vars
+}
+
+func (c *checker) push() { c.vars = append(c.vars, make(map[string]typ)) }
+
+func (c *checker) pop() { c.vars = c.vars[:len(c.vars)-1] }
+
+func (c *checker) lookup(name string) typ {
+	for i := len(c.vars) - 1; i >= 0; i-- {
+		if t, ok := c.vars[i][name]; ok {
+			return t
+		}
+	}
+	return typ{}
+}
+
+func (c *checker) walk(node parse.Node, dot typ) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		c.pipe(n.Pipe, dot)
+	case *parse.IfNode:
+		c.push()
+		c.pipe(n.Pipe, dot)
+		c.walk(n.List, dot)
+		c.walk(n.ElseList, dot)
+		c.pop()
+	case *parse.WithNode:
+		c.push()
+		t := c.pipe(n.Pipe, dot)
+		c.walk(n.List, t)
+		c.walk(n.ElseList, dot)
+		c.pop()
+	case *parse.RangeNode:
+		c.push()
+		key, elem := c.elem(c.eval(n.Pipe, dot))
+		switch len(n.Pipe.Decl) {
+		case 1:
+			c.declare(n.Pipe, elem)
+		case 2:
+			c.vars[len(c.vars)-1][n.Pipe.Decl[0].Ident[0]] = key
+			c.vars[len(c.vars)-1][n.Pipe.Decl[1].Ident[0]] = elem
+		}
+		c.walk(n.List, elem)
+		c.walk(n.ElseList, dot)
+		c.pop()
+	case *parse.TemplateNode:
+		var t typ
+		if n.Pipe != nil {
+			t = c.pipe(n.Pipe, dot)
+		}
+		c.template(n.Name, t)
+	}
+}
+
+// elem returns the types of the keys and elements produced by ranging over a
+// value of type t.
+func (c *checker) elem(t typ) (key, elem typ) {
+	if t.schema != nil {
+		s := t.schema.Resolve()
+		switch {
+		case s.Is("array") || s.Items != nil:
+			if s.Items != nil {
+				elem = t.child(s.Items)
+			}
+			return typ{goType: intType}, elem
+		case s.Is("object"):
+			if ap := s.AdditionalProperties; ap != nil && !ap.IsFalse() {
+				elem = t.child(ap)
+			}
+			return typ{goType: stringType}, elem
+		}
+		return typ{}, typ{}
+	}
+
+	if rt := t.goType; rt != nil {
+		switch rt.Kind() {
+		case reflect.Slice, reflect.Array:
+			return typ{goType: intType}, known(rt.Elem())
+		case reflect.Map:
+			return known(rt.Key()), known(rt.Elem())
+		}
+	}
+	return typ{}, typ{}
+}
+
+// known returns the typ for a Go type, treating interfaces as unknown since
+// the dynamic type can't be determined statically.
+func known(rt reflect.Type) typ {
+	if rt == nil || rt.Kind() == reflect.Interface {
+		return typ{}
+	}
+	return typ{goType: rt}
+}
+
+// pipe evaluates the pipeline and declares its variables.
+func (c *checker) pipe(p *parse.PipeNode, dot typ) typ {
+	t := c.eval(p, dot)
+	c.declare(p, t)
+	return t
+}
+
+func (c *checker) declare(p *parse.PipeNode, t typ) {
+	if p.IsAssign {
+		return
+	}
+	for _, d := range p.Decl {
+		c.vars[len(c.vars)-1][d.Ident[0]] = t
+	}
+}
+
+// eval returns the type of the pipeline without declaring its variables.
+func (c *checker) eval(p *parse.PipeNode, dot typ) typ {
+	var result typ
+	for i, cmd := range p.Cmds {
+		var final *typ
+		if i > 0 {
+			final = &result
+		}
+		result = c.command(cmd, dot, final)
+	}
+	return result
+}
+
+func (c *checker) command(cmd *parse.CommandNode, dot typ, final *typ) typ {
+	switch n := cmd.Args[0].(type) {
+	case *parse.IdentifierNode:
+		return c.call(n, cmd.Args[1:], dot, final)
+	case *parse.FieldNode:
+		// Arguments imply a method call, which can't be described by a
+		// schema.
+		if len(cmd.Args) > 1 || final != nil {
+			return typ{}
+		}
+	}
+	return c.arg(cmd.Args[0], dot)
+}
+
+// arg returns the type of a single operand.
+func (c *checker) arg(node parse.Node, dot typ) typ {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return c.fields(dot, "", n.Ident, n.Pos)
+	case *parse.VariableNode:
+		return c.fields(c.lookup(n.Ident[0]), n.Ident[0], n.Ident[1:], n.Pos)
+	case *parse.ChainNode:
+		return c.fields(c.arg(n.Node, dot), "("+n.Node.String()+")", n.Field, n.Pos)
+	case *parse.PipeNode:
+		return c.eval(n, dot)
+	case *parse.IdentifierNode:
+		return c.call(n, nil, dot, nil)
+	case *parse.StringNode, *parse.NumberNode, *parse.BoolNode, *parse.NilNode:
+		return typ{lit: n}
+	}
+	return typ{}
+}
+
+// fields returns the type of the field chain applied to a value of type t.
+// The prefix is the expression that t was evaluated from, and is used to build
+// error messages.
+func (c *checker) fields(t typ, prefix string, idents []string, pos parse.Pos) typ {
+	expr := prefix
+	for _, name := range idents {
+		if !t.known() {
+			return typ{}
+		}
+
+		var ok bool
+		t, ok = c.field(t, name)
+		if !ok {
+			if expr == "" {
+				c.l.report(RuleUnknownField, c.tree, pos, "unknown field .%s", name)
+			} else {
+				c.l.report(RuleUnknownField, c.tree, pos, "unknown field %s in %s", name, expr)
+			}
+			return typ{}
+		}
+		expr += "." + name
+	}
+	return t
+}
+
+// field returns the type of the named field of t. The second return value
+// reports whether the field is known to exist.
+func (c *checker) field(t typ, name string) (typ, bool) {
+	if t.schema != nil {
+		s := t.schema.Resolve()
+		if len(s.Type) > 0 && !s.Is("object") {
+			return typ{}, false
+		}
+
+		p, ok := s.Property(name)
+		if !ok {
+			return typ{}, false
+		}
+		return t.child(p), true
+	}
+
+	rt := t.goType
+	if rt == nil {
+		return typ{}, true
+	}
+
+	if m, ok := rt.MethodByName(name); ok {
+		if m.Type.NumOut() == 0 {
+			return typ{}, true
+		}
+		return known(m.Type.Out(0)), true
+	}
+
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	switch rt.Kind() {
+	case reflect.Struct:
+		if f, ok := rt.FieldByName(name); ok && f.PkgPath == "" {
+			return known(f.Type), true
+		}
+		return typ{}, false
+	case reflect.Map:
+		if rt.Key().Kind() == reflect.String {
+			return known(rt.Elem()), true
+		}
+	case reflect.Interface:
+		return typ{}, true
+	}
+	return typ{}, false
+}
+
+// call checks the arguments of a function call and returns the type of the
+// function's result.
+func (c *checker) call(id *parse.IdentifierNode, args []parse.Node, dot typ, final *typ) typ {
+	argTypes := make([]typ, 0, len(args)+1)
+	argNodes := make([]parse.Node, 0, len(args)+1)
+	for _, a := range args {
+		argTypes = append(argTypes, c.arg(a, dot))
+		argNodes = append(argNodes, a)
+	}
+	if final != nil {
+		argTypes = append(argTypes, *final)
+		argNodes = append(argNodes, id)
+	}
+
+	fn, ok := c.l.cfg.Funcs[id.Ident]
+	if !ok {
+		return builtinResult(id.Ident, argTypes)
+	}
+
+	ft := reflect.TypeOf(fn)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return typ{}
+	}
+
+	// Calls with the wrong number of arguments are reported by the arity
+	// rule.
+	n := ft.NumIn()
+	if (ft.IsVariadic() && len(argTypes) < n-1) || (!ft.IsVariadic() && len(argTypes) != n) {
+		return typ{}
+	}
+
+	for i, at := range argTypes {
+		var pt reflect.Type
+		if ft.IsVariadic() && i >= n-1 {
+			pt = ft.In(n - 1).Elem()
+		} else {
+			pt = ft.In(i)
+		}
+
+		if !assignable(at, pt) {
+			c.l.report(RuleFuncArgType, c.tree, argNodes[i].Position(),
+				"argument %d of %s has type %s, want %s", i+1, id.Ident, at, pt)
+		}
+	}
+
+	if ft.NumOut() == 0 {
+		return typ{}
+	}
+	return known(ft.Out(0))
+}
+
+// builtinResult returns the type produced by the text/template builtin
+// functions.
+func builtinResult(name string, args []typ) typ {
+	switch name {
+	case "len":
+		return typ{goType: intType}
+	case "not", "eq", "ne", "lt", "le", "gt", "ge":
+		return typ{goType: boolType}
+	case "print", "printf", "println", "html", "js", "urlquery":
+		return typ{goType: stringType}
+	case "slice":
+		if len(args) > 0 {
+			return args[0]
+		}
+	case "index":
+		if len(args) == 2 && args[0].schema != nil {
+			s := args[0].schema.Resolve()
+			if s.Items != nil {
+				return args[0].child(s.Items)
+			}
+			if str, ok := args[1].lit.(*parse.StringNode); ok {
+				if p, ok := s.Property(str.Text); ok {
+					return args[0].child(p)
+				}
+			}
+		}
+	}
+	return typ{}
+}
+
+// assignable determines if a value of type t can be passed as an argument of
+// type pt, following the conversion rules used by text/template.
+func assignable(t typ, pt reflect.Type) bool {
+	if pt.Kind() == reflect.Interface && pt.NumMethod() == 0 {
+		return true
+	}
+
+	switch n := t.lit.(type) {
+	case *parse.StringNode:
+		return pt.Kind() == reflect.String
+	case *parse.BoolNode:
+		return pt.Kind() == reflect.Bool
+	case *parse.NilNode:
+		switch pt.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+			return true
+		}
+		return false
+	case *parse.NumberNode:
+		switch pt.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return n.IsInt
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return n.IsUint
+		case reflect.Float32, reflect.Float64:
+			return n.IsFloat
+		case reflect.Complex64, reflect.Complex128:
+			return n.IsComplex
+		}
+		return false
+	}
+
+	rt := t.runtime()
+	if rt == nil || rt.Kind() == reflect.Interface {
+		return true
+	}
+	if rt.AssignableTo(pt) {
+		return true
+	}
+	if pt.Kind() == reflect.Interface {
+		return rt.Implements(pt)
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// ApplyDefaults fills in the default values of missing object properties. The
+// value is expected to be the result of decoding JSON into an interface{}.
+// Objects are modified in place, and the resulting value is returned. If v is
+// nil and the schema has a default, the default is returned.
+func (s *Schema) ApplyDefaults(v interface{}) interface{} {
+	s = s.Resolve()
+	if s == nil {
+		return v
+	}
+
+	if v == nil {
+		if s.Default == nil {
+			return nil
+		}
+		v = clone(s.Default)
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, name := range sortedSchemaKeys(s.Properties) {
+			p := s.Properties[name]
+			if cur, ok := val[name]; ok {
+				val[name] = p.ApplyDefaults(cur)
+			} else if d := p.Resolve().Default; d != nil {
+				val[name] = p.ApplyDefaults(clone(d))
+			}
+		}
+		if ap := s.AdditionalProperties; ap != nil {
+			for name, cur := range val {
+				if _, ok := s.Properties[name]; !ok {
+					val[name] = ap.ApplyDefaults(cur)
+				}
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range val {
+				val[i] = s.Items.ApplyDefaults(item)
+			}
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		v = sub.ApplyDefaults(v)
+	}
+
+	return v
+}
+
+// clone creates a deep copy of a decoded JSON value so that defaults are never
+// shared between the schema and the data.
+func clone(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var c interface{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return v
+	}
+	return c
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedSchemaKeys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	numberType = reflect.TypeOf(json.Number(""))
+	bytesType  = reflect.TypeOf([]byte(nil))
+)
+
+// Reflect generates a schema describing the JSON encoding of v, as produced by
+// encoding/json. Struct fields are named according to their json tags. Fields
+// without the omitempty option are required, and structs do not permit
+// additional properties.
+//
+// Recursive types are described up to the first repetition, after which any
+// value is permitted.
+func Reflect(v interface{}) *Schema {
+	return reflectType(reflect.TypeOf(v), make(map[reflect.Type]bool))
+}
+
+func reflectType(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	if t == nil {
+		return Bool(true)
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case numberType:
+		return &Schema{Type: Types{"number"}}
+	case bytesType:
+		return &Schema{Type: Types{"string"}}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: reflectType(t.Elem(), seen)}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: reflectType(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return Bool(true)
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		s := &Schema{
+			Type:                 Types{"object"},
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: Bool(false),
+		}
+		reflectFields(s, t, seen)
+		return s
+	}
+
+	return Bool(true)
+}
+
+func reflectFields(s *Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name, opts := f.Name, ""
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if i := strings.Index(tag, ","); i >= 0 {
+				tag, opts = tag[:i], tag[i:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		// Embedded structs without a name have their fields promoted.
+		if f.Anonymous && name == f.Name {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				reflectFields(s, ft, seen)
+				continue
+			}
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		s.Properties[name] = reflectType(f.Type, seen)
+		if !strings.Contains(opts, ",omitempty") && f.Type.Kind() != reflect.Ptr {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+// Package schema implements the subset of JSON Schema used by temple to
+// describe and validate template data.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// Schema is a JSON Schema. Only the keywords needed to describe and validate
+// template data are supported, and format is treated as an annotation. Local
+// references of the form "#/definitions/Name" or "#/$defs/Name" are resolved
+// when the schema is parsed.
+//
+// JSON Schema also allows the boolean values true and false to be used as
+// schemas. These are represented by a Schema created with Bool.
+type Schema struct {
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        Types  `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+
+	Default interface{}   `json:"default,omitempty"`
+	Enum    []interface{} `json:"enum,omitempty"`
+	Const   interface{}   `json:"const,omitempty"`
+	// HasConst reports whether the const keyword is present, which is needed
+	// to tell a const of null apart from no const. It is set when the schema
+	// is parsed.
+	HasConst bool `json:"-"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	Items       *Schema `json:"items,omitempty"`
+	MinItems    *int    `json:"minItems,omitempty"`
+	MaxItems    *int    `json:"maxItems,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+
+	// boolean is set for the boolean schemas true and false.
+	boolean *bool
+	// ref is the resolved target of Ref.
+	ref *Schema
+	// pattern is the compiled Pattern.
+	pattern *regexp.Regexp
+}
+
+// Bool returns the boolean schema b. The schema true accepts any value, and
+// the schema false accepts no values.
+func Bool(b bool) *Schema {
+	return &Schema{boolean: &b}
+}
+
+// Load reads and parses the schema in the named file.
+func Load(filename string) (*Schema, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return s, nil
+}
+
+// Parse parses a JSON encoded schema and resolves its references.
+func Parse(b []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveRefs(&s, make(map[*Schema]bool)); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both object and boolean schemas
+// are supported.
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	var v bool
+	if err := json.Unmarshal(b, &v); err == nil {
+		*s = Schema{boolean: &v}
+		return nil
+	}
+
+	type schema Schema
+	if err := json.Unmarshal(b, (*schema)(s)); err != nil {
+		return err
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return err
+	}
+	_, s.HasConst = keys["const"]
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.boolean != nil {
+		return json.Marshal(*s.boolean)
+	}
+
+	type schema Schema
+	if s.HasConst && s.Const == nil {
+		// The const field is omitted when it is empty, so a const of null is
+		// added by a field that shadows it.
+		return json.Marshal(struct {
+			*schema
+			Const interface{} `json:"const"`
+		}{schema: (*schema)(s)})
+	}
+	return json.Marshal((*schema)(s))
+}
+
+// IsTrue determines if s is the boolean schema true.
+func (s *Schema) IsTrue() bool { return s.boolean != nil && *s.boolean }
+
+// IsFalse determines if s is the boolean schema false.
+func (s *Schema) IsFalse() bool { return s.boolean != nil && !*s.boolean }
+
+// Resolve follows the references of s and returns the referenced schema. If s
+// does not contain a reference, s is returned.
+func (s *Schema) Resolve() *Schema {
+	for i := 0; s != nil && s.ref != nil && i < 100; i++ {
+		s = s.ref
+	}
+	return s
+}
+
+// Is determines if s explicitly permits values of the provided type. Since
+// every integer is also a number, an integer schema is considered a number.
+func (s *Schema) Is(typ string) bool {
+	for _, t := range s.Resolve().Type {
+		if t == typ || (t == "integer" && typ == "number") {
+			return true
+		}
+	}
+	return false
+}
+
+// Property returns the schema of the named property of an object. The second
+// return value reports whether the property is known.
+//
+// Unlike validation, an absent additionalProperties keyword does not permit
+// properties missing from Properties unless Properties is also absent. This
+// allows misspelled property names to be detected.
+func (s *Schema) Property(name string) (*Schema, bool) {
+	s = s.Resolve()
+
+	if p, ok := s.Properties[name]; ok {
+		return p, true
+	}
+
+	if ap := s.AdditionalProperties; ap != nil {
+		return ap, !ap.IsFalse()
+	}
+
+	if len(s.Properties) == 0 {
+		return Bool(true), true
+	}
+	return nil, false
+}
+
+func (s *Schema) resolveRefs(root *Schema, seen map[*Schema]bool) error {
+	if s == nil || seen[s] {
+		return nil
+	}
+	seen[s] = true
+
+	if s.Ref != "" {
+		target, err := root.lookup(s.Ref)
+		if err != nil {
+			return err
+		}
+		s.ref = target
+	}
+
+	for _, m := range []map[string]*Schema{s.Properties, s.Definitions, s.Defs} {
+		for _, c := range m {
+			if err := c.resolveRefs(root, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range []*Schema{s.AdditionalProperties, s.Items, s.Not} {
+		if err := c.resolveRefs(root, seen); err != nil {
+			return err
+		}
+	}
+
+	for _, l := range [][]*Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, c := range l {
+			if err := c.resolveRefs(root, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+
+	return nil
+}
+
+// lookup finds the schema referenced by a local JSON Pointer such as
+// "#/definitions/Address".
+func (s *Schema) lookup(ref string) (*Schema, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported reference %q: only local references are supported", ref)
+	}
+
+	cur := s
+	segments := strings.Split(strings.TrimPrefix(ref, "#"), "/")
+	for i := 1; i < len(segments); i++ {
+		seg := unescapePointer(segments[i])
+
+		var next *Schema
+		switch seg {
+		case "definitions", "$defs", "properties":
+			if i+1 == len(segments) {
+				return nil, fmt.Errorf("invalid reference %q", ref)
+			}
+			i++
+			key := unescapePointer(segments[i])
+			switch seg {
+			case "definitions":
+				next = cur.Definitions[key]
+			case "$defs":
+				next = cur.Defs[key]
+			case "properties":
+				next = cur.Properties[key]
+			}
+		case "items":
+			next = cur.Items
+		case "additionalProperties":
+			next = cur.AdditionalProperties
+		}
+
+		if next == nil {
+			return nil, fmt.Errorf("unresolved reference %q", ref)
+		}
+		cur = next
+	}
+
+	return cur, nil
+}
+
+func unescapePointer(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
+
+// Types is the value of the type keyword. In JSON, it can be either a single
+// type name or an array of type names.
+type Types []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Types) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*t = Types{s}
+		return nil
+	}
+
+	var ts []string
+	if err := json.Unmarshal(b, &ts); err != nil {
+		return err
+	}
+	*t = Types(ts)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
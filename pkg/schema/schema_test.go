@@ -0,0 +1,202 @@
+package schema
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(`{
+		"type": "object",
+		"properties": {
+			"Customer": {"$ref": "#/definitions/Customer"},
+			"Tags": {"type": ["array", "null"], "items": true}
+		},
+		"definitions": {
+			"Customer": {"type": "object", "properties": {"Name": {"type": "string"}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	c, ok := s.Property("Customer")
+	if !ok {
+		t.Fatalf("Property(Customer) not found")
+	}
+	if name, ok := c.Property("Name"); !ok || !name.Is("string") {
+		t.Errorf("Property(Name) = %v, %v, want string schema", name, ok)
+	}
+	if _, ok := c.Property("Nmae"); ok {
+		t.Errorf("Property(Nmae) found, want unknown property")
+	}
+
+	tags, _ := s.Property("Tags")
+	if !reflect.DeepEqual(tags.Type, Types{"array", "null"}) || !tags.Items.IsTrue() {
+		t.Errorf("Property(Tags) = %+v, want array or null with items true", tags)
+	}
+
+	if _, err := Parse([]byte(`{"$ref": "#/definitions/Missing"}`)); err == nil {
+		t.Errorf("Parse() expected error for unresolved reference")
+	}
+}
+
+func TestReflect(t *testing.T) {
+	type Address struct {
+		Street string
+	}
+	type Data struct {
+		Name    string            `json:"name"`
+		Age     int               `json:"age,omitempty"`
+		Address *Address          `json:"address"`
+		Tags    []string          `json:"tags"`
+		Extra   map[string]string `json:"-"`
+		private int
+	}
+
+	got, err := json.Marshal(Reflect(Data{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"type":"object","properties":{` +
+		`"address":{"type":"object","properties":{"Street":{"type":"string"}},"required":["Street"],"additionalProperties":false},` +
+		`"age":{"type":"integer"},` +
+		`"name":{"type":"string"},` +
+		`"tags":{"type":"array","items":{"type":"string"}}},` +
+		`"required":["name","tags"],"additionalProperties":false}`
+	if string(got) != want {
+		t.Errorf("Reflect() = %s, want %s", got, want)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := Parse([]byte(`{
+		"type": "object",
+		"required": ["id", "items"],
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"email": {"type": "string", "pattern": "^[^@]+@[^@]+$"},
+			"status": {"enum": ["open", "closed"]},
+			"items": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/item"}}
+		},
+		"additionalProperties": false,
+		"$defs": {
+			"item": {"type": "object", "required": ["sku"], "properties": {"sku": {"type": "string", "maxLength": 4}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "Valid",
+			data: `{"id": 1, "email": "a@b", "status": "open", "items": [{"sku": "A1"}]}`,
+		},
+		{
+			name: "All violations",
+			data: `{"id": 1.5, "email": "ab", "status": "lost", "extra": true, "items": [{"sku": "ABCDE"}, {}]}`,
+			want: []string{
+				"/email: value must match the pattern \"^[^@]+@[^@]+$\"",
+				"/extra: property is not allowed",
+				"/id: expected integer, got number",
+				"/items/0/sku: length must be at most 4, got 5",
+				"/items/1/sku: required property is missing",
+				"/status: value must be one of \"open\", \"closed\"",
+			},
+		},
+		{
+			name: "Wrong root type",
+			data: `[]`,
+			want: []string{"/: expected object, got array"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data interface{}
+			if err := json.Unmarshal([]byte(tt.data), &data); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			if err := s.Validate(data); err != nil {
+				for _, e := range err.(ValidationErrors) {
+					got = append(got, e.Error())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Integers too large for an int64 may be decoded as *big.Int.
+	id, _ := new(big.Int).SetString("123456789012345678901234", 10)
+	data := map[string]interface{}{"id": id, "items": []interface{}{map[string]interface{}{"sku": "A1"}}}
+	if err := s.Validate(data); err != nil {
+		t.Errorf("Validate() with a *big.Int = %v, want nil", err)
+	}
+	data["id"] = big.NewRat(3, 2)
+	if err := s.Validate(data); err == nil || err.Error() != "/id: expected integer, got number" {
+		t.Errorf("Validate() with a *big.Rat = %v, want an integer error", err)
+	}
+}
+
+func TestSchema_Validate_constNull(t *testing.T) {
+	s, err := Parse([]byte(`{"properties": {"deleted": {"const": null}, "name": {}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Validate(map[string]interface{}{"deleted": nil, "name": nil}); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	err = s.Validate(map[string]interface{}{"deleted": false})
+	if err == nil || err.Error() != "/deleted: value must be null" {
+		t.Errorf("Validate() = %v, want /deleted: value must be null", err)
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"properties":{"deleted":{"const":null},"name":{}}}`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestSchema_ApplyDefaults(t *testing.T) {
+	s, err := Parse([]byte(`{
+		"type": "object",
+		"properties": {
+			"currency": {"type": "string", "default": "USD"},
+			"options": {"type": "object", "default": {}, "properties": {"tax": {"default": 0.2}}},
+			"lines": {"type": "array", "items": {"properties": {"qty": {"default": 1}}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(`{"currency": "EUR", "lines": [{}, {"qty": 3}]}`), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(s.ApplyDefaults(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"currency":"EUR","lines":[{"qty":1},{"qty":3}],"options":{"tax":0.2}}`
+	if string(got) != want {
+		t.Errorf("ApplyDefaults() = %s, want %s", got, want)
+	}
+}
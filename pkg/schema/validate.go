@@ -0,0 +1,324 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// A ValidationError describes a single value that does not match its schema.
+type ValidationError struct {
+	// Pointer is the JSON Pointer to the invalid value. The empty string
+	// refers to the whole document.
+	Pointer string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	p := e.Pointer
+	if p == "" {
+		p = "/"
+	}
+	return p + ": " + e.Message
+}
+
+// ValidationErrors contains every violation found while validating a value.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Validate checks v against the schema. The value is expected to be the
+// result of decoding JSON into an interface{}. If any violations are found, a
+// ValidationErrors containing all of them is returned.
+func (s *Schema) Validate(v interface{}) error {
+	var errs ValidationErrors
+	s.validate(v, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *Schema) validate(v interface{}, ptr string, errs *ValidationErrors) {
+	s = s.Resolve()
+	if s == nil || s.IsTrue() {
+		return
+	}
+
+	report := func(format string, args ...interface{}) {
+		*errs = append(*errs, ValidationError{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.IsFalse() {
+		report("no value is allowed")
+		return
+	}
+
+	if len(s.Type) > 0 && !s.matchesType(v) {
+		report("expected %s, got %s", strings.Join(s.Type, " or "), typeName(v))
+		return
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if equal(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("value must be one of %s", formatValues(s.Enum))
+		}
+	}
+
+	if (s.Const != nil || s.HasConst) && !equal(v, s.Const) {
+		report("value must be %s", formatValues([]interface{}{s.Const}))
+	}
+
+	switch val := v.(type) {
+	case string:
+		s.validateString(val, report)
+	case map[string]interface{}:
+		s.validateObject(val, ptr, errs)
+	case []interface{}:
+		s.validateArray(val, ptr, errs, report)
+	default:
+		if f, ok := number(v); ok {
+			s.validateNumber(f, report)
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		sub.validate(v, ptr, errs)
+	}
+
+	if len(s.AnyOf) > 0 && countValid(s.AnyOf, v) == 0 {
+		report("value does not match any of the allowed schemas")
+	}
+
+	if len(s.OneOf) > 0 {
+		if n := countValid(s.OneOf, v); n != 1 {
+			report("value must match exactly one schema, matched %d", n)
+		}
+	}
+
+	if s.Not != nil && s.Not.Validate(v) == nil {
+		report("value matches a disallowed schema")
+	}
+}
+
+func countValid(schemas []*Schema, v interface{}) int {
+	n := 0
+	for _, s := range schemas {
+		if s.Validate(v) == nil {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Schema) validateString(v string, report func(string, ...interface{})) {
+	n := utf8.RuneCountInString(v)
+	if s.MinLength != nil && n < *s.MinLength {
+		report("length must be at least %d, got %d", *s.MinLength, n)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		report("length must be at most %d, got %d", *s.MaxLength, n)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(v) {
+		report("value must match the pattern %q", s.Pattern)
+	}
+}
+
+func (s *Schema) validateNumber(v float64, report func(string, ...interface{})) {
+	if s.Minimum != nil && v < *s.Minimum {
+		report("value must be at least %v, got %v", *s.Minimum, v)
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		report("value must be at most %v, got %v", *s.Maximum, v)
+	}
+	if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+		report("value must be greater than %v, got %v", *s.ExclusiveMinimum, v)
+	}
+	if s.ExclusiveMaximum != nil && v >= *s.ExclusiveMaximum {
+		report("value must be less than %v, got %v", *s.ExclusiveMaximum, v)
+	}
+	if s.MultipleOf != nil && *s.MultipleOf != 0 {
+		if q := v / *s.MultipleOf; q != math.Trunc(q) {
+			report("value must be a multiple of %v, got %v", *s.MultipleOf, v)
+		}
+	}
+}
+
+func (s *Schema) validateObject(v map[string]interface{}, ptr string, errs *ValidationErrors) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			*errs = append(*errs, ValidationError{
+				Pointer: ptr + "/" + escapePointer(name),
+				Message: "required property is missing",
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(v) {
+		p := ptr + "/" + escapePointer(name)
+		if sub, ok := s.Properties[name]; ok {
+			sub.validate(v[name], p, errs)
+		} else if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.IsFalse() {
+				*errs = append(*errs, ValidationError{Pointer: p, Message: "property is not allowed"})
+			} else {
+				s.AdditionalProperties.validate(v[name], p, errs)
+			}
+		}
+	}
+}
+
+func (s *Schema) validateArray(v []interface{}, ptr string, errs *ValidationErrors, report func(string, ...interface{})) {
+	if s.MinItems != nil && len(v) < *s.MinItems {
+		report("array must contain at least %d items, got %d", *s.MinItems, len(v))
+	}
+	if s.MaxItems != nil && len(v) > *s.MaxItems {
+		report("array must contain at most %d items, got %d", *s.MaxItems, len(v))
+	}
+
+	if s.UniqueItems {
+	outer:
+		for i := range v {
+			for j := 0; j < i; j++ {
+				if equal(v[i], v[j]) {
+					report("array items must be unique, items %d and %d are equal", j, i)
+					break outer
+				}
+			}
+		}
+	}
+
+	if s.Items != nil {
+		for i, item := range v {
+			s.Items.validate(item, fmt.Sprintf("%s/%d", ptr, i), errs)
+		}
+	}
+}
+
+// matchesType determines if v is one of the types permitted by the schema.
+func (s *Schema) matchesType(v interface{}) bool {
+	for _, t := range s.Type {
+		switch t {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case "number":
+			if _, ok := number(v); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := number(v); ok && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeName returns the JSON type name of v.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if f, ok := number(v); ok {
+		if f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// number converts the numeric types produced by decoding JSON to a float64.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f, true
+	case *big.Float:
+		f, _ := n.Float64()
+		return f, true
+	case *big.Rat:
+		f, _ := n.Float64()
+		return f, true
+	}
+	return 0, false
+}
+
+// equal determines if two decoded JSON values are equal. Numbers are compared
+// by value regardless of their Go type.
+func equal(a, b interface{}) bool {
+	fa, aok := number(a)
+	fb, bok := number(b)
+	if aok || bok {
+		return aok && bok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func formatValues(vals []interface{}) string {
+	parts := make([]string, len(vals))
+	for i, v := range vals {
+		b, err := json.Marshal(v)
+		if err != nil {
+			parts[i] = fmt.Sprint(v)
+		} else {
+			parts[i] = string(b)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
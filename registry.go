@@ -0,0 +1,259 @@
+package temple
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Categories of the functions provided by temple.
+const (
+	CategoryStrings    = "strings"
+	CategoryNumbers    = "numbers"
+	CategoryConversion = "conversion"
+	CategoryCollection = "collection"
+	CategoryDecimal    = "decimal"
+	CategoryStatistics = "statistics"
+	CategoryMath       = "math"
+	CategoryFiles      = "files"
+	CategorySprig      = "sprig"
+)
+
+// Capability describes a sensitive operation performed by a template function.
+// Capabilities can be combined with the | operator.
+type Capability uint
+
+// Capabilities of template functions.
+const (
+	// CapFile is the capability of reading or inspecting files.
+	CapFile Capability = 1 << iota
+	// CapEnv is the capability of reading environment variables.
+	CapEnv
+	// CapNetwork is the capability of making network requests.
+	CapNetwork
+	// CapNondeterministic is the capability of producing different output
+	// for the same input, such as the current time or random numbers.
+	CapNondeterministic
+)
+
+var capabilityNames = []struct {
+	c    Capability
+	name string
+}{
+	{CapFile, "file"},
+	{CapEnv, "env"},
+	{CapNetwork, "network"},
+	{CapNondeterministic, "nondeterministic"},
+}
+
+// String returns the names of the capabilities separated by "|".
+func (c Capability) String() string {
+	var names []string
+	for _, n := range capabilityNames {
+		if c&n.c != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// ParseCapability returns the capability with the provided name. The valid
+// names are file, env, network and nondeterministic.
+func ParseCapability(s string) (Capability, error) {
+	for _, n := range capabilityNames {
+		if s == n.name {
+			return n.c, nil
+		}
+	}
+	return 0, fmt.Errorf("temple: unknown capability %q", s)
+}
+
+// Func describes a template function. The argument and return types are
+// derived from Fn using reflection.
+type Func struct {
+	Name        string
+	Fn          interface{}
+	Category    string
+	Description string
+	// Capabilities are the sensitive operations performed by the function.
+	Capabilities Capability
+	// Examples contains template snippets demonstrating the function. Each
+	// example may be followed by " -> " and the expected output.
+	Examples []string
+}
+
+func (f *Func) typ() reflect.Type { return reflect.TypeOf(f.Fn) }
+
+// Args returns the types of the function's arguments. If the function is
+// variadic, the final type is a slice.
+func (f *Func) Args() []reflect.Type {
+	t := f.typ()
+	args := make([]reflect.Type, t.NumIn())
+	for i := range args {
+		args[i] = t.In(i)
+	}
+	return args
+}
+
+// Returns returns the types of the function's return values.
+func (f *Func) Returns() []reflect.Type {
+	t := f.typ()
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+	return out
+}
+
+// Variadic determines if the function accepts a variable number of arguments.
+func (f *Func) Variadic() bool { return f.typ().IsVariadic() }
+
+// Signature returns the Go signature of the function, such as
+//		FormatMask(string, string) (string, error)
+func (f *Func) Signature() string {
+	return f.Name + strings.TrimPrefix(f.typ().String(), "func")
+}
+
+// Registry stores template functions along with their metadata. It is safe
+// for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	funcs map[string]*Func
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{funcs: make(map[string]*Func)}
+}
+
+// Register adds functions to the registry. An error is returned if a function
+// is not a func or a function with the same name is already registered.
+func (r *Registry) Register(funcs ...Func) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range funcs {
+		if f.Fn == nil || reflect.TypeOf(f.Fn).Kind() != reflect.Func {
+			return fmt.Errorf("temple: %s is not a function", f.Name)
+		}
+		if _, ok := r.funcs[f.Name]; ok {
+			return fmt.Errorf("temple: %s is already registered", f.Name)
+		}
+
+		f := f
+		r.funcs[f.Name] = &f
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r *Registry) MustRegister(funcs ...Func) *Registry {
+	if err := r.Register(funcs...); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Lookup returns the named function.
+func (r *Registry) Lookup(name string) (*Func, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, ok := r.funcs[name]
+	return f, ok
+}
+
+// LookupFunc returns the registered function whose implementation is fn. This
+// can be used to find the metadata of a function after it has been added to a
+// FuncMap, possibly under a different name. Implementations are compared by
+// their code, so method values bound to different receivers, such as the file
+// functions of different FileSystems, match the same registered function.
+// Closures created by the same function literal share their code as well. If
+// several registered functions share the implementation of fn, the one
+// registered under name is returned, or none if there is no such function.
+func (r *Registry) LookupFunc(name string, fn interface{}) (*Func, bool) {
+	funcs := r.lookupImpl(fn)
+	for _, f := range funcs {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	if len(funcs) != 1 {
+		return nil, false
+	}
+	return funcs[0], true
+}
+
+// lookupImpl returns the registered functions whose implementation is fn,
+// sorted by name.
+func (r *Registry) lookupImpl(fn interface{}) []*Func {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*Func
+	for _, f := range r.funcs {
+		if reflect.ValueOf(f.Fn).Pointer() == v.Pointer() {
+			out = append(out, f)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Funcs returns all registered functions in the provided categories, sorted
+// by name. If no categories are provided, all functions are returned.
+func (r *Registry) Funcs(categories ...string) []*Func {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	want := make(map[string]bool)
+	for _, c := range categories {
+		want[c] = true
+	}
+
+	var out []*Func
+	for _, f := range r.funcs {
+		if len(want) == 0 || want[f.Category] {
+			out = append(out, f)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Categories returns the sorted names of all categories in the registry.
+func (r *Registry) Categories() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, f := range r.funcs {
+		if !seen[f.Category] {
+			seen[f.Category] = true
+			out = append(out, f.Category)
+		}
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// FuncMap creates a new FuncMap containing the functions in the provided
+// categories. If no categories are provided, all functions are included.
+func (r *Registry) FuncMap(categories ...string) FuncMap {
+	f := make(FuncMap)
+	for _, fn := range r.Funcs(categories...) {
+		f[fn.Name] = fn.Fn
+	}
+	return f
+}
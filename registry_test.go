@@ -0,0 +1,107 @@
+package temple
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFunc_Signature(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   Func
+		want string
+	}{
+		{
+			name: "FormatMask",
+			fn:   Func{Name: "FormatMask", Fn: FormatMask},
+			want: "FormatMask(string, string) (string, error)",
+		},
+		{
+			name: "Variadic",
+			fn:   Func{Name: "NewList", Fn: NewList},
+			want: "NewList(...interface {}) temple.List",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn.Signature(); got != tt.want {
+				t.Errorf("Signature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(Func{Name: "ToUpper", Fn: ToUpper, Category: "a"}, Func{Name: "ToLower", Fn: ToLower, Category: "b"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if err := r.Register(Func{Name: "ToUpper", Fn: ToUpper}); err == nil {
+		t.Errorf("Register() expected error for duplicate name")
+	}
+	if err := r.Register(Func{Name: "NotAFunc", Fn: 1}); err == nil {
+		t.Errorf("Register() expected error for non-function")
+	}
+
+	if got, want := r.Categories(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Categories() = %v, want %v", got, want)
+	}
+
+	f := r.FuncMap("b")
+	if _, ok := f["ToLower"]; !ok || len(f) != 1 {
+		t.Errorf("FuncMap(b) = %v, want only ToLower", f)
+	}
+
+	fn, ok := r.LookupFunc("ToUpper", ToUpper)
+	if !ok || fn.Name != "ToUpper" {
+		t.Errorf("LookupFunc(ToUpper) = %v, %v, want ToUpper", fn, ok)
+	}
+	if fn, ok := r.LookupFunc("Upper", ToUpper); !ok || fn.Name != "ToUpper" {
+		t.Errorf("LookupFunc(Upper, ToUpper) = %v, %v, want ToUpper", fn, ok)
+	}
+	if fn, ok := r.LookupFunc("ToUpper", ToString); ok {
+		t.Errorf("LookupFunc(ToUpper, ToString) = %v, want no function", fn)
+	}
+}
+
+func TestRegistry_LookupFunc_closures(t *testing.T) {
+	prefix := func(p string) func(string) string {
+		return func(s string) string { return p + s }
+	}
+	a, b := prefix("a"), prefix("b")
+
+	r := NewRegistry().MustRegister(Func{Name: "a", Fn: a, Category: "a"}, Func{Name: "b", Fn: b, Category: "b"})
+	for _, name := range []string{"a", "b"} {
+		fn, ok := r.LookupFunc(name, r.FuncMap()[name])
+		if !ok || fn.Category != name {
+			t.Errorf("LookupFunc(%s) = %v, %v, want category %s", name, fn, ok, name)
+		}
+	}
+	if fn, ok := r.LookupFunc("c", a); ok {
+		t.Errorf("LookupFunc(c) = %v, want no function for an ambiguous closure", fn)
+	}
+}
+
+func TestFullFuncMap(t *testing.T) {
+	full := FullFuncMap()
+	for _, m := range []FuncMap{StringsFuncs, NumbersFuncs, ConversionFuncs, CollectionFuncs} {
+		for name := range m {
+			if _, ok := full[name]; !ok {
+				t.Errorf("FullFuncMap() missing %s", name)
+			}
+		}
+	}
+
+	for name := range FileFuncs {
+		if _, ok := full[name]; ok {
+			t.Errorf("FullFuncMap() contains file function %s", name)
+		}
+	}
+
+	for _, f := range DefaultRegistry.Funcs() {
+		if f.Description == "" {
+			t.Errorf("%s has no description", f.Name)
+		}
+	}
+}
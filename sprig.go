@@ -0,0 +1,197 @@
+package temple
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/spf13/cast"
+)
+
+// SprigCompatFuncMap maps the functions of github.com/Masterminds/sprig that
+// overlap with temple to their lowercase sprig names. The functions accept
+// their arguments in the same order as sprig and convert them the same way,
+// then call the temple function implementing the operation. Most notably, the
+// integer arithmetic functions such as add and div convert their arguments to
+// int64 instead of float64, and conversion failures result in zero values
+// instead of errors.
+//
+// Where sprig panics or silently wraps around, such as when dividing by zero
+// or when an int64 overflows, the compatibility functions return an error.
+func SprigCompatFuncMap() FuncMap {
+	return DefaultRegistry.FuncMap(CategorySprig)
+}
+
+func sprigAdd(i ...interface{}) (int64, error) {
+	s, err := IntSum(int64(0), sprigInt64s(i)...)
+	return s, funcError("add", err, i...)
+}
+
+func sprigAdd1(i interface{}) (int64, error) {
+	s, err := IntSum(cast.ToInt64(i), 1)
+	return s, funcError("add1", err, i)
+}
+
+func sprigSub(a, b interface{}) (int64, error) {
+	d, err := IntDiff(cast.ToInt64(a), cast.ToInt64(b))
+	return d, funcError("sub", err, a, b)
+}
+
+func sprigMul(a interface{}, v ...interface{}) (int64, error) {
+	p, err := IntMul(cast.ToInt64(a), sprigInt64s(v)...)
+	return p, funcError("mul", err, append([]interface{}{a}, v...)...)
+}
+
+func sprigDiv(a, b interface{}) (int64, error) {
+	q, err := IntDiv(cast.ToInt64(a), cast.ToInt64(b))
+	return q, funcError("div", err, a, b)
+}
+
+func sprigMod(a, b interface{}) (int64, error) {
+	r, err := IntMod(cast.ToInt64(a), cast.ToInt64(b))
+	return r, funcError("mod", err, a, b)
+}
+
+func sprigMax(a interface{}, i ...interface{}) (int64, error) {
+	m, err := IntMax(cast.ToInt64(a), sprigInt64s(i)...)
+	return int64(m), funcError("max", err, append([]interface{}{a}, i...)...)
+}
+
+func sprigMin(a interface{}, i ...interface{}) (int64, error) {
+	m, err := IntMin(cast.ToInt64(a), sprigInt64s(i)...)
+	return int64(m), funcError("min", err, append([]interface{}{a}, i...)...)
+}
+
+func sprigMaxf(a interface{}, i ...interface{}) (float64, error) {
+	m, err := FloatMax(cast.ToFloat64(a), sprigFloat64s(i)...)
+	return m, funcError("maxf", err, append([]interface{}{a}, i...)...)
+}
+
+func sprigMinf(a interface{}, i ...interface{}) (float64, error) {
+	m, err := FloatMin(cast.ToFloat64(a), sprigFloat64s(i)...)
+	return m, funcError("minf", err, append([]interface{}{a}, i...)...)
+}
+
+func sprigFloor(a interface{}) float64 { return Floor(cast.ToFloat64(a)) }
+
+func sprigCeil(a interface{}) float64 { return Ceil(cast.ToFloat64(a)) }
+
+func sprigJoin(sep string, v interface{}) (string, error) {
+	s, err := Join(sep, sprigStrings(v))
+	return s, funcError("join", err, sep, v)
+}
+
+// sprigInt, sprigInt64 and sprigFloat64 return zero instead of an error when
+// the value cannot be converted, like sprig.
+func sprigInt(v interface{}) int {
+	i, _ := ToInt(v)
+	return i
+}
+
+func sprigInt64(v interface{}) int64 {
+	i, _ := ToInt(v)
+	return int64(i)
+}
+
+func sprigFloat64(v interface{}) float64 {
+	f, _ := ToFloat64(v)
+	return f
+}
+
+func sprigAtoi(a string) int {
+	i, _ := strconv.Atoi(a)
+	return i
+}
+
+func sprigList(v ...interface{}) List { return NewList(v...) }
+
+// sprigHas reports whether a list contains the needle. Unlike sprig's has,
+// which panics when the haystack is not a list, an error is returned.
+func sprigHas(needle interface{}, haystack interface{}) (bool, error) {
+	if haystack == nil {
+		return false, nil
+	}
+
+	v := reflect.ValueOf(haystack)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false, funcError("has", ErrInvalidType, needle, haystack)
+	}
+
+	l := make(List, v.Len())
+	for i := range l {
+		l[i] = v.Index(i).Interface()
+	}
+	ok, err := Contains(needle, l)
+	return ok, funcError("has", err, needle, haystack)
+}
+
+// sprigInt64s converts values to int64 like sprig's arithmetic functions.
+func sprigInt64s(vals []interface{}) []interface{} {
+	out := make([]interface{}, len(vals))
+	for i, v := range vals {
+		out[i] = cast.ToInt64(v)
+	}
+	return out
+}
+
+// sprigFloat64s converts values to float64 like sprig's maxf and minf.
+func sprigFloat64s(vals []interface{}) []interface{} {
+	out := make([]interface{}, len(vals))
+	for i, v := range vals {
+		out[i] = cast.ToFloat64(v)
+	}
+	return out
+}
+
+// sprigString converts a value to a string in the same way as sprig's
+// toString. It differs from ToString, which formats floats without an
+// exponent and rejects values of other types.
+func sprigString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// sprigStrings converts a value to a slice of strings in the same way as
+// sprig's toStrings. Nil elements are skipped, and a value that is not a list
+// becomes a single element.
+func sprigStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, s := range v {
+			if s != nil {
+				out = append(out, sprigString(s))
+			}
+		}
+		return out
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Array, reflect.Slice:
+		out := make([]string, 0, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			if e := val.Index(i).Interface(); e != nil {
+				out = append(out, sprigString(e))
+			}
+		}
+		return out
+	}
+
+	if v == nil {
+		return []string{}
+	}
+	return []string{sprigString(v)}
+}
@@ -0,0 +1,42 @@
+//go:build sprig
+// +build sprig
+
+package temple
+
+import (
+	"strings"
+	"testing"
+	texttmpl "text/template"
+
+	sprig "github.com/Masterminds/sprig/v3"
+)
+
+// TestSprigParity runs the cases of TestSprigCompatFuncMap with sprig and
+// with SprigCompatFuncMap and compares the results. It is only built with the
+// sprig tag:
+//		go test -tags sprig -run TestSprigParity
+func TestSprigParity(t *testing.T) {
+	for _, tt := range sprigCompatTests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, wantErr := executeSprig(tt.tmpl, tt.data, sprig.TxtFuncMap())
+			got, err := executeSprig(tt.tmpl, tt.data, SprigCompatFuncMap().Text())
+			if (err != nil) != (wantErr != nil) {
+				t.Fatalf("error = %v, sprig error = %v", err, wantErr)
+			}
+			if got != want {
+				t.Errorf("Execute() = %q, sprig = %q", got, want)
+			}
+		})
+	}
+}
+
+func executeSprig(tmpl string, data interface{}, funcs texttmpl.FuncMap) (string, error) {
+	t, err := texttmpl.New("").Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	err = t.Execute(&b, data)
+	return b.String(), err
+}
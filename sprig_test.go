@@ -0,0 +1,122 @@
+package temple
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	texttmpl "text/template"
+)
+
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+// sprigCompatTests contains the expected output of the compatibility functions.
+// TestSprigParity checks the same cases against sprig itself.
+var sprigCompatTests = []struct {
+	name    string
+	tmpl    string
+	data    interface{}
+	want    string
+	wantErr bool
+}{
+	{name: "add", tmpl: `{{ add 1 2 3 }}`, want: "6"},
+	{name: "add no args", tmpl: `{{ add }}`, want: "0"},
+	{name: "add truncates floats", tmpl: `{{ add 1.9 1 }}`, want: "2"},
+	{name: "add strings", tmpl: `{{ add "2" 3 }}`, want: "5"},
+	{name: "add1", tmpl: `{{ add1 5 }}`, want: "6"},
+	{name: "sub", tmpl: `{{ sub 5 7 }}`, want: "-2"},
+	{name: "mul", tmpl: `{{ mul 2 3 4 }}`, want: "24"},
+	{name: "div", tmpl: `{{ div 7 2 }}`, want: "3"},
+	{name: "div by zero", tmpl: `{{ div 7 0 }}`, wantErr: true},
+	{name: "mod by zero", tmpl: `{{ mod 7 0 }}`, wantErr: true},
+	{name: "mod", tmpl: `{{ mod 7 2 }}`, want: "1"},
+	{name: "max", tmpl: `{{ max 1 5 3 }}`, want: "5"},
+	{name: "max single", tmpl: `{{ max 4 }}`, want: "4"},
+	{name: "min", tmpl: `{{ min 4 1 3 }}`, want: "1"},
+	{name: "maxf", tmpl: `{{ maxf 1.5 2.5 "3.5" }}`, want: "3.5"},
+	{name: "minf", tmpl: `{{ minf 1.5 2.5 }}`, want: "1.5"},
+	{name: "floor", tmpl: `{{ floor 1.5 }}`, want: "1"},
+	{name: "floor string", tmpl: `{{ floor "-1.5" }}`, want: "-2"},
+	{name: "ceil", tmpl: `{{ ceil 1.2 }}`, want: "2"},
+	{name: "upper", tmpl: `{{ upper "abc" }}`, want: "ABC"},
+	{name: "lower", tmpl: `{{ lower "ABC" }}`, want: "abc"},
+	{name: "join strings", tmpl: `{{ join ", " .X }}`, data: map[string]interface{}{"X": []string{"a", "b"}}, want: "a, b"},
+	{name: "join list", tmpl: `{{ join "-" (list 1 "b" nil 2.5) }}`, want: "1-b-2.5"},
+	{name: "join ints", tmpl: `{{ join "+" .X }}`, data: map[string]interface{}{"X": []int{1, 2}}, want: "1+2"},
+	{name: "join scalar", tmpl: `{{ join "," "abc" }}`, want: "abc"},
+	{name: "join nil", tmpl: `{{ join "," nil }}`, want: ""},
+	{name: "toString", tmpl: `{{ toString 5 }}`, want: "5"},
+	{name: "toString stringer", tmpl: `{{ toString .X }}`, data: map[string]interface{}{"X": stringer{}}, want: "stringer"},
+	{name: "toString error", tmpl: `{{ toString .X }}`, data: map[string]interface{}{"X": errors.New("boom")}, want: "boom"},
+	{name: "toStrings", tmpl: `{{ toStrings (list 1 2) }}`, want: "[1 2]"},
+	{name: "int", tmpl: `{{ int "5" }}`, want: "5"},
+	{name: "int invalid", tmpl: `{{ int "five" }}`, want: "0"},
+	{name: "int64", tmpl: `{{ int64 5.7 }}`, want: "5"},
+	{name: "float64", tmpl: `{{ float64 "1.5" }}`, want: "1.5"},
+	{name: "atoi", tmpl: `{{ atoi "42" }}`, want: "42"},
+	{name: "atoi invalid", tmpl: `{{ atoi "4.2" }}`, want: "0"},
+	{name: "list", tmpl: `{{ list 1 "a" }}`, want: "[1 a]"},
+	{name: "has", tmpl: `{{ has 2 (list 1 2 3) }}`, want: "true"},
+	{name: "has missing", tmpl: `{{ has "2" (list 1 2 3) }}`, want: "false"},
+	{name: "has nil", tmpl: `{{ has 1 nil }}`, want: "false"},
+	{name: "has list element", tmpl: `{{ has (list 1) (list (list 1) 2) }}`, want: "true"},
+	{name: "has not a list", tmpl: `{{ has 1 "abc" }}`, wantErr: true},
+}
+
+// TestSprigCompatFuncMap verifies the output of the compatibility functions.
+// Run the tests with -tags sprig to compare them against sprig.
+func TestSprigCompatFuncMap(t *testing.T) {
+	tests := sprigCompatTests
+	tested := make(map[string]bool)
+	for _, tt := range tests {
+		tested[strings.Fields(tt.tmpl)[1]] = true
+	}
+	for name := range SprigCompatFuncMap() {
+		if !tested[name] {
+			t.Errorf("%s is not covered by the parity tests", name)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := texttmpl.New("").Funcs(SprigCompatFuncMap().Text()).Parse(tt.tmpl)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var b strings.Builder
+			err = tmpl.Execute(&b, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := b.String(); !tt.wantErr && got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSprigCompatErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() error
+		want error
+	}{
+		{name: "div", fn: func() error { _, err := sprigDiv(7, 0); return err }, want: ErrDivisionByZero},
+		{name: "mod", fn: func() error { _, err := sprigMod(7, "0"); return err }, want: ErrDivisionByZero},
+		{name: "has", fn: func() error { _, err := sprigHas(1, "abc"); return err }, want: ErrInvalidType},
+		{name: "add", fn: func() error { _, err := sprigAdd(int64(math.MaxInt64), 1); return err }, want: ErrOverflow},
+		{name: "mul", fn: func() error { _, err := sprigMul(int64(1)<<62, 4); return err }, want: ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fn()
+			var fe *FuncError
+			if !errors.As(err, &fe) || fe.Func != tt.name || !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v from %s", err, tt.want, tt.name)
+			}
+		})
+	}
+}
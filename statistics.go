@@ -0,0 +1,298 @@
+package temple
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// By returns the values of a field of each element of a collection. The
+// elements may be maps with string keys, structs, or pointers to either. The
+// field may be a path of names separated by dots, such as "Customer.Age". An
+// error is returned if an element does not have the field.
+//
+// The statistics functions accept the same arguments as Max, so By can be used
+// to aggregate a field of a list of maps or structs:
+//		{{ Mean (By "Price" .Items) }}
+//		{{ .Items | By "Price" | Percentile 90 }}
+func By(field string, collection interface{}) (List, error) {
+	l, err := by(field, collection)
+	return l, funcError("By", err, field, collection)
+}
+
+func by(field string, collection interface{}) (List, error) {
+	v := indirectValue(reflect.ValueOf(collection))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, ErrInvalidType
+	}
+
+	path := strings.Split(field, ".")
+	out := make(List, v.Len())
+	for i := range out {
+		e := v.Index(i)
+		for _, name := range path {
+			var err error
+			if e, err = fieldValue(e, name); err != nil {
+				return nil, fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+		out[i] = e.Interface()
+	}
+	return out, nil
+}
+
+func fieldValue(v reflect.Value, name string) (reflect.Value, error) {
+	v = indirectValue(v)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		e := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !e.IsValid() {
+			return reflect.Value{}, fmt.Errorf("missing field %q", name)
+		}
+		return e, nil
+	case reflect.Struct:
+		f, ok := v.Type().FieldByName(name)
+		if !ok || f.PkgPath != "" {
+			return reflect.Value{}, fmt.Errorf("missing field %q", name)
+		}
+		return v.FieldByIndex(f.Index), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot get field %q of %s", name, v.Kind())
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// Count returns the number of numbers.
+func Count(arg1 interface{}, arg2 ...interface{}) (int, error) {
+	nums, _, err := parseNumbers(append([]interface{}{arg1}, arg2...))
+	if err != nil {
+		return 0, funcError("Count", err, append([]interface{}{arg1}, arg2...)...)
+	}
+	return len(nums), nil
+}
+
+// SumOf adds the numbers. Unlike Sum, the result is an int if all of the
+// numbers are integers, and a float64 otherwise. If any of the numbers is big,
+// the sum is exact and the result is a big number like for Max. The sum of no
+// numbers is 0. ErrOverflow is returned if a sum of integers does not fit in an
+// int64.
+func SumOf(arg1 interface{}, arg2 ...interface{}) (interface{}, error) {
+	nums, float, err := parseNumbers(append([]interface{}{arg1}, arg2...))
+	if err != nil {
+		return nil, funcError("SumOf", err, append([]interface{}{arg1}, arg2...)...)
+	}
+
+	if hasBig(nums) {
+		sum, err := sumRat(nums)
+		if err != nil {
+			return nil, funcError("SumOf", err, append([]interface{}{arg1}, arg2...)...)
+		}
+		return bigValue(sum, float), nil
+	}
+
+	if float {
+		var sum float64
+		for _, n := range nums {
+			sum += n.float64()
+		}
+		return sum, nil
+	}
+
+	var sum int64
+	for _, n := range nums {
+		var ok bool
+		if sum, ok = addInt64(sum, n.i); !ok {
+			return nil, funcError("SumOf", ErrOverflow, append([]interface{}{arg1}, arg2...)...)
+		}
+	}
+	return int(sum), nil
+}
+
+// Mean returns the arithmetic mean of the numbers as a float64. If any of the
+// numbers is big, the mean is computed exactly and returned as a Decimal.
+func Mean(arg1 interface{}, arg2 ...interface{}) (interface{}, error) {
+	v, err := meanOf(append([]interface{}{arg1}, arg2...))
+	if err != nil {
+		return nil, funcError("Mean", err, append([]interface{}{arg1}, arg2...)...)
+	}
+	return v, nil
+}
+
+func meanOf(args []interface{}) (interface{}, error) {
+	nums, _, err := parseNumbers(args)
+	if err != nil {
+		return nil, err
+	} else if len(nums) == 0 {
+		return nil, ErrEmpty
+	}
+
+	if hasBig(nums) {
+		sum, err := sumRat(nums)
+		if err != nil {
+			return nil, err
+		}
+		return ratDecimal(sum.Quo(sum, new(big.Rat).SetInt64(int64(len(nums))))), nil
+	}
+
+	vals := make([]float64, len(nums))
+	for i, n := range nums {
+		vals[i] = n.float64()
+	}
+	return mean(vals), nil
+}
+
+// Median returns the middle number, or the mean of the two middle numbers if
+// there is an even number of numbers.
+func Median(arg1 interface{}, arg2 ...interface{}) (float64, error) {
+	vals, err := statArgs(arg1, arg2)
+	if err != nil {
+		return 0, funcError("Median", err, append([]interface{}{arg1}, arg2...)...)
+	}
+	return percentile(vals, 50), nil
+}
+
+// Mode returns the most frequent number. If several numbers are equally
+// frequent, the smallest of them is returned. The type of the result is the
+// same as for Max.
+func Mode(arg1 interface{}, arg2 ...interface{}) (interface{}, error) {
+	nums, float, err := parseNumbers(append([]interface{}{arg1}, arg2...))
+	if err == nil && len(nums) == 0 {
+		err = ErrEmpty
+	}
+	if err != nil {
+		return nil, funcError("Mode", err, append([]interface{}{arg1}, arg2...)...)
+	}
+
+	isBig := hasBig(nums)
+	counts := make(map[string]int)
+	values := make(map[string]number)
+	for _, n := range nums {
+		k := modeKey(n, float, isBig)
+		counts[k]++
+		values[k] = n
+	}
+
+	var mode number
+	max := 0
+	for k, c := range counts {
+		if n := values[k]; c > max || (c == max && compareNumbers(n, mode) < 0) {
+			mode, max = n, c
+		}
+	}
+	return numberValue(mode, float, isBig), nil
+}
+
+// modeKey returns a key that is the same for equal numbers, comparing them as
+// they are returned by numberValue.
+func modeKey(n number, float, isBig bool) string {
+	if r := n.rat(); isBig && r != nil {
+		return r.RatString()
+	}
+	if float {
+		return strconv.FormatFloat(n.float64(), 'g', -1, 64)
+	}
+	return strconv.FormatInt(n.i, 10)
+}
+
+// sumRat adds the numbers exactly. NaN and infinite values are rejected.
+func sumRat(nums []number) (*big.Rat, error) {
+	sum := new(big.Rat)
+	for _, n := range nums {
+		r := n.rat()
+		if r == nil {
+			return nil, fmt.Errorf("%w: %v is not a finite number", ErrDomain, n.f)
+		}
+		sum.Add(sum, r)
+	}
+	return sum, nil
+}
+
+// Variance returns the population variance of the numbers.
+func Variance(arg1 interface{}, arg2 ...interface{}) (float64, error) {
+	vals, err := statArgs(arg1, arg2)
+	if err != nil {
+		return 0, funcError("Variance", err, append([]interface{}{arg1}, arg2...)...)
+	}
+	return variance(vals), nil
+}
+
+// StdDev returns the population standard deviation of the numbers.
+func StdDev(arg1 interface{}, arg2 ...interface{}) (float64, error) {
+	vals, err := statArgs(arg1, arg2)
+	if err != nil {
+		return 0, funcError("StdDev", err, append([]interface{}{arg1}, arg2...)...)
+	}
+	return math.Sqrt(variance(vals)), nil
+}
+
+// Percentile returns the pth percentile of the numbers, where p is between 0
+// and 100. Values between two numbers are linearly interpolated.
+//		{{ Percentile 90 .Latencies }}
+func Percentile(p interface{}, arg1 interface{}, arg2 ...interface{}) (float64, error) {
+	vals, err := statArgs(arg1, arg2)
+	var n number
+	if err == nil {
+		n, err = toNumber(p)
+	}
+	if err == nil && (n.float64() < 0 || n.float64() > 100 || math.IsNaN(n.float64())) {
+		err = fmt.Errorf("percentile %v is not between 0 and 100", p)
+	}
+	if err != nil {
+		return 0, funcError("Percentile", err, append([]interface{}{p, arg1}, arg2...)...)
+	}
+	return percentile(vals, n.float64()), nil
+}
+
+// statArgs flattens the arguments of the statistics functions into float64s.
+// At least one number is required.
+func statArgs(arg1 interface{}, arg2 []interface{}) ([]float64, error) {
+	vals, err := parseFloatArgs(arg1, arg2...)
+	if err != nil {
+		return nil, err
+	} else if len(vals) == 0 {
+		return nil, ErrEmpty
+	}
+	return vals, nil
+}
+
+func mean(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
+
+func variance(vals []float64) float64 {
+	m := mean(vals)
+
+	var sum float64
+	for _, v := range vals {
+		sum += (v - m) * (v - m)
+	}
+	return sum / float64(len(vals))
+}
+
+func percentile(vals []float64, p float64) float64 {
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	if lo == len(sorted)-1 {
+		return sorted[lo]
+	}
+	return sorted[lo] + (rank-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
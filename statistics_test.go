@@ -0,0 +1,144 @@
+package temple
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	texttmpl "text/template"
+)
+
+func TestStatistics(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() (interface{}, error)
+		want    interface{}
+		wantErr error
+	}{
+		{name: "Count", fn: func() (interface{}, error) { return Count(NewList(1, 2), []int{3}) }, want: 3},
+		{name: "Count empty", fn: func() (interface{}, error) { return Count([]int{}) }, want: 0},
+		{name: "SumOf ints", fn: func() (interface{}, error) { return SumOf(1, []int64{2, 3}) }, want: 6},
+		{name: "SumOf floats", fn: func() (interface{}, error) { return SumOf(1, 0.5) }, want: 1.5},
+		{name: "SumOf overflow", fn: func() (interface{}, error) { return SumOf(int64(math.MaxInt64), 1) }, wantErr: ErrOverflow},
+		{name: "SumOf json.Number", fn: func() (interface{}, error) { return SumOf(json.Number("2"), "3") }, want: 5},
+		{name: "Mean", fn: func() (interface{}, error) { return Mean(1, 2, 3, 4) }, want: 2.5},
+		{name: "Mean empty", fn: func() (interface{}, error) { return Mean([]float64{}) }, wantErr: ErrEmpty},
+		{name: "Median odd", fn: func() (interface{}, error) { return Median(3, 1, 2) }, want: 2.0},
+		{name: "Median even", fn: func() (interface{}, error) { return Median([]int{4, 1, 3, 2}) }, want: 2.5},
+		{name: "Mode", fn: func() (interface{}, error) { return Mode(1, 2, 2, 3) }, want: 2},
+		{name: "Mode tie", fn: func() (interface{}, error) { return Mode(3, 3, -1, -1, 5) }, want: -1},
+		{name: "Mode floats", fn: func() (interface{}, error) { return Mode(1, 1.5, 1.5) }, want: 1.5},
+		{name: "Variance", fn: func() (interface{}, error) { return Variance(2, 4, 4, 4, 5, 5, 7, 9) }, want: 4.0},
+		{name: "StdDev", fn: func() (interface{}, error) { return StdDev(NewList(2, 4, 4, 4, 5, 5, 7, 9)) }, want: 2.0},
+		{name: "Percentile", fn: func() (interface{}, error) { return Percentile(90, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}) }, want: 10.0},
+		{name: "Percentile interpolated", fn: func() (interface{}, error) { return Percentile(25, 1, 2, 3, 4) }, want: 1.75},
+		{name: "Percentile max", fn: func() (interface{}, error) { return Percentile(100, 5, 1) }, want: 5.0},
+		{name: "Percentile out of range", fn: func() (interface{}, error) { return Percentile(101, 1) }, wantErr: errors.New("")},
+		{name: "not numeric", fn: func() (interface{}, error) { return Mean("a") }, wantErr: ErrNotNumeric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if tt.wantErr.Error() != "" && !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestBy(t *testing.T) {
+	type customer struct {
+		Age int
+	}
+	type order struct {
+		Total    float64
+		Customer *customer
+		secret   int
+	}
+
+	tests := []struct {
+		name       string
+		field      string
+		collection interface{}
+		want       List
+		wantErr    bool
+	}{
+		{
+			name:       "maps",
+			field:      "Price",
+			collection: []interface{}{map[string]interface{}{"Price": 1.5}, map[string]interface{}{"Price": 2}},
+			want:       List{1.5, 2},
+		},
+		{
+			name:       "structs",
+			field:      "Total",
+			collection: []order{{Total: 1}, {Total: 2}},
+			want:       List{1.0, 2.0},
+		},
+		{
+			name:       "path",
+			field:      "Customer.Age",
+			collection: []*order{{Customer: &customer{Age: 30}}, {Customer: &customer{Age: 40}}},
+			want:       List{30, 40},
+		},
+		{
+			name:       "missing key",
+			field:      "Price",
+			collection: []interface{}{map[string]interface{}{"Cost": 1}},
+			wantErr:    true,
+		},
+		{
+			name:       "unexported field",
+			field:      "secret",
+			collection: []order{{}},
+			wantErr:    true,
+		},
+		{
+			name:       "not a collection",
+			field:      "Price",
+			collection: 1,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := By(tt.field, tt.collection)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("By() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("By() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatisticsTemplate(t *testing.T) {
+	data, err := DecodeJSON([]byte(`{"Items": [{"Price": 10}, {"Price": 20}, {"Price": 60}]}`), NumbersInt64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := texttmpl.Must(texttmpl.New("").Funcs(StatisticsFuncs.Text()).Parse(
+		`{{ Mean (By "Price" .Items) }} {{ .Items | By "Price" | Percentile 50 }} {{ By "Price" .Items | SumOf }}`,
+	))
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := b.String(), "30 20 90"; got != want {
+		t.Errorf("Execute() = %q, want %q", got, want)
+	}
+}
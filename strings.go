@@ -1,7 +1,12 @@
 package temple
 
 import (
+	"encoding/json"
 	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -44,10 +49,15 @@ func ToLower(s string) string {
 	return strings.ToLower(s)
 }
 
-// Commas adds a comma after every three digits.
-func Commas(s string) (string, error) {
-	if !IsNumeric(s) {
-		return "", errors.New("non numeric string")
+// Commas adds a comma after every three digits. The argument may be a numeric
+// string or a number of any kind, including json.Number, Decimal and big number
+// values. Floats are formatted with the fewest digits that represent them
+// exactly, so use FormatFixed or DecFormat first to control the number of
+// decimal places.
+func Commas(v interface{}) (string, error) {
+	s, ok := numericString(v)
+	if !ok || !IsNumeric(s) {
+		return "", funcError("Commas", ErrNotNumeric, v)
 	}
 
 	if s[0] == '.' {
@@ -82,6 +92,34 @@ func Commas(s string) (string, error) {
 	return strings.Join(parts, "."), nil
 }
 
+// numericString converts the argument of Commas to a string.
+func numericString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return string(v), true
+	case Decimal, *big.Int, *big.Float, *big.Rat:
+		d, err := toDecimal(v)
+		return d.String(), err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(f, 'f', -1, rv.Type().Bits()), true
+	}
+	return "", false
+}
+
 func numCommas(s string) int {
 	l := len(s)
 
@@ -132,17 +170,17 @@ func Join(sep string, a interface{}) (out string, err error) {
 	case []interface{}:
 		sl, err := ToStringSlice(s)
 		if err != nil {
-			return "", err
+			return "", funcError("Join", err, sep, a)
 		}
 		out = strings.Join(sl, sep)
 	case List:
 		sl, err := ToStringSlice(s)
 		if err != nil {
-			return "", err
+			return "", funcError("Join", err, sep, a)
 		}
 		out = strings.Join(sl, sep)
 	default:
-		return "", errors.New("slice of strings required")
+		return "", funcError("Join", errors.New("slice of strings required"), sep, a)
 	}
 	return out, nil
 }
@@ -166,7 +204,7 @@ func FormatMask(mask string, str string) (string, error) {
 
 	mRunes, sRunes := []rune(mask), []rune(str)
 	if len(mRunes) < len(sRunes) {
-		return "", errors.New("mask too short for string")
+		return "", funcError("FormatMask", errors.New("mask too short for string"), mask, str)
 	}
 
 	j := 0
@@ -189,7 +227,7 @@ func FormatMask(mask string, str string) (string, error) {
 		}
 
 		if j >= len(sRunes) {
-			return "", errors.New("too few string characters for mask")
+			return "", funcError("FormatMask", errors.New("too few string characters for mask"), mask, str)
 		}
 
 		mRunes[i] = sRunes[j]
@@ -197,7 +235,7 @@ func FormatMask(mask string, str string) (string, error) {
 	}
 
 	if j != len(sRunes) {
-		return "", errors.New("unused string characters")
+		return "", funcError("FormatMask", errors.New("unused string characters"), mask, str)
 	}
 
 	return string(mRunes), nil
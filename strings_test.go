@@ -1,6 +1,8 @@
 package temple
 
 import (
+	"encoding/json"
+	"math/big"
 	"testing"
 )
 
@@ -55,7 +57,7 @@ func Test_numCommas(t *testing.T) {
 
 func TestCommas(t *testing.T) {
 	type args struct {
-		s string
+		s interface{}
 	}
 	tests := []struct {
 		name    string
@@ -111,6 +113,78 @@ func TestCommas(t *testing.T) {
 			want:    ".56",
 			wantErr: false,
 		},
+		{
+			name:    "int",
+			args:    args{s: -1234567},
+			want:    "-1,234,567",
+			wantErr: false,
+		},
+		{
+			name:    "uint64",
+			args:    args{s: uint64(18446744073709551615)},
+			want:    "18,446,744,073,709,551,615",
+			wantErr: false,
+		},
+		{
+			name:    "float",
+			args:    args{s: 1234.5},
+			want:    "1,234.5",
+			wantErr: false,
+		},
+		{
+			name:    "large float",
+			args:    args{s: 1e21},
+			want:    "1,000,000,000,000,000,000,000",
+			wantErr: false,
+		},
+		{
+			name:    "json.Number",
+			args:    args{s: json.Number("9007199254740993")},
+			want:    "9,007,199,254,740,993",
+			wantErr: false,
+		},
+		{
+			name:    "Decimal",
+			args:    args{s: Decimal{unscaled: big.NewInt(123456), scale: 2}},
+			want:    "1,234.56",
+			wantErr: false,
+		},
+		{
+			name:    "big.Int",
+			args:    args{s: bigInt("-123456789012345678901234567890")},
+			want:    "-123,456,789,012,345,678,901,234,567,890",
+			wantErr: false,
+		},
+		{
+			name:    "big.Float",
+			args:    args{s: big.NewFloat(1234.5)},
+			want:    "1,234.5",
+			wantErr: false,
+		},
+		{
+			name:    "big.Rat",
+			args:    args{s: big.NewRat(12345, 4)},
+			want:    "3,086.25",
+			wantErr: false,
+		},
+		{
+			name:    "nil big.Int",
+			args:    args{s: (*big.Int)(nil)},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "not numeric",
+			args:    args{s: "abc"},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "invalid type",
+			args:    args{s: true},
+			want:    "",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,264 @@
+// Package templatetest runs golden file tests for templates.
+//
+// A test case is a template file ending in .tmpl. The data passed to the
+// template is read from a sibling file with the same name ending in
+// .data.json, if one exists. The expected output is read from a sibling file
+// ending in .golden. For example, the case "invoice" consists of
+//		invoice.tmpl
+//		invoice.data.json
+//		invoice.golden
+//
+// Template files whose names begin with an underscore are partials. They are
+// not test cases themselves, but are parsed alongside every case in the same
+// directory so that their templates can be used by the cases.
+package templatetest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltmpl "html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	texttmpl "text/template"
+
+	"github.com/mattmeyers/temple"
+	"github.com/mattmeyers/temple/pkg/diff"
+)
+
+// File extensions used to discover test cases.
+const (
+	TemplateExt = ".tmpl"
+	DataExt     = ".data.json"
+	GoldenExt   = ".golden"
+)
+
+// A Case is a single golden file test.
+type Case struct {
+	// Name is the path of the template relative to the searched directory,
+	// without the template extension.
+	Name     string
+	Template string
+	// Data is the path of the data file, or the empty string if the case
+	// has no data.
+	Data     string
+	Golden   string
+	Partials []string
+}
+
+// Options control how cases are rendered.
+type Options struct {
+	// Funcs contains the functions available to the templates.
+	Funcs temple.FuncMap
+	// HTML indicates that html/template should be used.
+	HTML bool
+	// Numbers determines the Go type of the numbers in the data files.
+	Numbers temple.NumberMode
+	// Limits bounds the resources used while rendering each case.
+	Limits temple.Limits
+	// Update indicates that the golden files should be rewritten with the
+	// rendered output instead of being compared to it.
+	Update bool
+}
+
+// A Result is the outcome of running a Case.
+type Result struct {
+	Case Case
+	// Err is set if the case could not be rendered.
+	Err error
+	// Diff is a unified diff from the golden file to the rendered output.
+	// It is empty if the output matches.
+	Diff string
+	// Updated is set if the golden file was rewritten.
+	Updated bool
+}
+
+// Failed determines if the case failed.
+func (r Result) Failed() bool {
+	return r.Err != nil || r.Diff != ""
+}
+
+// Discover finds all cases in dir and its subdirectories. Cases are returned
+// sorted by name.
+func Discover(dir string) ([]Case, error) {
+	partials := make(map[string][]string)
+	var templates []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, TemplateExt) {
+			return nil
+		}
+
+		if strings.HasPrefix(info.Name(), "_") {
+			d := filepath.Dir(path)
+			partials[d] = append(partials[d], path)
+		} else {
+			templates = append(templates, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]Case, 0, len(templates))
+	for _, t := range templates {
+		base := strings.TrimSuffix(t, TemplateExt)
+
+		name, err := filepath.Rel(dir, base)
+		if err != nil {
+			return nil, err
+		}
+
+		c := Case{
+			Name:     filepath.ToSlash(name),
+			Template: t,
+			Golden:   base + GoldenExt,
+			Partials: partials[filepath.Dir(t)],
+		}
+		if _, err := os.Stat(base + DataExt); err == nil {
+			c.Data = base + DataExt
+		}
+		cases = append(cases, c)
+	}
+
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, nil
+}
+
+// Run discovers and runs all cases in dir.
+func Run(dir string, opts Options) ([]Result, error) {
+	cases, err := Discover(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(cases))
+	for i, c := range cases {
+		results[i] = RunCase(c, opts)
+	}
+	return results, nil
+}
+
+// RunCase renders a single case and compares the output to its golden file.
+func RunCase(c Case, opts Options) Result {
+	r := Result{Case: c}
+
+	var b bytes.Buffer
+	if err := render(&b, c, opts); err != nil {
+		r.Err = err
+		return r
+	}
+
+	if opts.Update {
+		if err := ioutil.WriteFile(c.Golden, b.Bytes(), 0644); err != nil {
+			r.Err = err
+		} else {
+			r.Updated = true
+		}
+		return r
+	}
+
+	golden, err := ioutil.ReadFile(c.Golden)
+	if os.IsNotExist(err) {
+		r.Err = fmt.Errorf("%s: golden file does not exist, run with -update to create it", c.Golden)
+		return r
+	} else if err != nil {
+		r.Err = err
+		return r
+	}
+
+	r.Diff = diff.Unified(c.Golden, c.Name+" (rendered)", string(golden), b.String())
+	return r
+}
+
+// Test runs every case in dir as a subtest of t. This allows template
+// repositories to use go test as their regression suite:
+//		var update = flag.Bool("update", false, "update golden files")
+//
+//		func TestTemplates(t *testing.T) {
+//			templatetest.Test(t, "testdata", templatetest.Options{
+//				Funcs:  temple.FullFuncMap(),
+//				Update: *update,
+//			})
+//		}
+func Test(t *testing.T, dir string, opts Options) {
+	t.Helper()
+
+	cases, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatalf("no test cases found in %s", dir)
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			r := RunCase(c, opts)
+			if r.Err != nil {
+				t.Fatal(r.Err)
+			}
+			if r.Diff != "" {
+				t.Errorf("output does not match golden file:\n%s", r.Diff)
+			}
+		})
+	}
+}
+
+func render(w io.Writer, c Case, opts Options) error {
+	data, err := readData(c.Data, opts.Numbers)
+	if err != nil {
+		return err
+	}
+
+	files := append([]string{c.Template}, c.Partials...)
+	name := filepath.Base(c.Template)
+
+	if opts.HTML {
+		t := htmltmpl.New(name)
+		t, err := t.Funcs(temple.HTMLIncludeFuncs(t, opts.Funcs).HTML()).
+			Funcs(opts.Funcs.HTML()).
+			ParseFiles(files...)
+		if err != nil {
+			return err
+		}
+		return opts.Limits.ExecuteHTML(context.Background(), t, w, data)
+	}
+
+	t := texttmpl.New(name)
+	t, err = t.Funcs(temple.TextIncludeFuncs(t).Text()).
+		Funcs(opts.Funcs.Text()).
+		ParseFiles(files...)
+	if err != nil {
+		return err
+	}
+	return opts.Limits.ExecuteText(context.Background(), t, w, data)
+}
+
+func readData(filename string, mode temple.NumberMode) (interface{}, error) {
+	if filename == "" {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := temple.DecodeJSON(b, mode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return data, nil
+}
+
@@ -0,0 +1,79 @@
+package templatetest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mattmeyers/temple"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templatetest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"_partial.tmpl":       `{{ define "greeting" }}Hello, {{ . }}{{ end }}`,
+		"hello.tmpl":          `{{ template "greeting" .Name }}!`,
+		"hello.data.json":     `{"Name": "World"}`,
+		"hello.golden":        `Hello, World!`,
+		"sub/upper.tmpl":      `{{ ToUpper "a" }}`,
+		"sub/upper.golden":    `a`,
+		"sub/missing.tmpl":    `x`,
+		"sub/_ignored.golden": `unused`,
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := Options{Funcs: temple.FuncMap{"ToUpper": temple.ToUpper}}
+
+	results, err := Run(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]Result)
+	for _, r := range results {
+		got[r.Case.Name] = r
+	}
+	if len(got) != 3 {
+		t.Fatalf("Run() found %d cases, want 3", len(got))
+	}
+	if r := got["hello"]; r.Failed() {
+		t.Errorf("hello failed: %v %s", r.Err, r.Diff)
+	}
+	if r := got["sub/upper"]; r.Err != nil || !strings.Contains(r.Diff, "-a\n") || !strings.Contains(r.Diff, "+A\n") {
+		t.Errorf("sub/upper = %v %q, want diff from a to A", r.Err, r.Diff)
+	}
+	if r := got["sub/missing"]; r.Err == nil {
+		t.Errorf("sub/missing expected error for missing golden file")
+	}
+
+	opts.Update = true
+	if _, err := Run(dir, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Update = false
+	results, err = Run(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Failed() {
+			t.Errorf("%s failed after update: %v %s", r.Case.Name, r.Err, r.Diff)
+		}
+	}
+}